@@ -0,0 +1,10 @@
+resource "porkbun_dns_record" "dmarc" {
+  domain = "example.com"
+  name   = "_dmarc"
+  type   = "TXT"
+  content = provider::porkbun::dmarc({
+    policy = "reject"
+    rua    = ["mailto:dmarc@example.com"]
+    adkim  = "strict"
+  })
+}
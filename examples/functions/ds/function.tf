@@ -0,0 +1,7 @@
+locals {
+  ds = provider::porkbun::ds("example.com", file("Kexample.com.+013+12345.key"))
+}
+
+output "ds_record" {
+  value = "${local.ds.key_tag} ${local.ds.algorithm} 2 ${local.ds.sha256}"
+}
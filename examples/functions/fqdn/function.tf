@@ -0,0 +1,3 @@
+output "fqdn" {
+  value = provider::porkbun::fqdn(porkbun_dns_record.www.name, porkbun_dns_record.www.domain)
+}
@@ -0,0 +1,6 @@
+resource "porkbun_dns_record" "mta_sts" {
+  domain  = "example.com"
+  name    = "_mta-sts"
+  type    = "TXT"
+  content = provider::porkbun::mta_sts("20240101000000")
+}
@@ -0,0 +1,14 @@
+locals {
+  records = provider::porkbun::parse_zone(file("example.com.zone"), "example.com")
+}
+
+resource "porkbun_dns_record" "zone" {
+  for_each = { for r in local.records : "${r.name}/${r.type}/${r.content}" => r }
+
+  domain  = "example.com"
+  name    = each.value.name
+  type    = each.value.type
+  content = each.value.content
+  ttl     = each.value.ttl
+  prio    = each.value.prio
+}
@@ -0,0 +1,6 @@
+resource "porkbun_dns_record" "mail" {
+  domain  = "example.com"
+  name    = provider::porkbun::relative_name("mail.example.com.", "example.com")
+  type    = "A"
+  content = "192.0.2.25"
+}
@@ -0,0 +1,4 @@
+resource "local_file" "zone" {
+  filename = "example.com.zone"
+  content  = "$ORIGIN example.com.\n${provider::porkbun::render_zone(porkbun_dns_record.zone)}"
+}
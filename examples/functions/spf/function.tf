@@ -0,0 +1,5 @@
+resource "porkbun_dns_record" "spf" {
+  domain  = "example.com"
+  type    = "TXT"
+  content = provider::porkbun::spf(["mx", "include:_spf.google.com", "ip4:192.0.2.0/24"], "-all")
+}
@@ -0,0 +1,6 @@
+resource "porkbun_dns_record" "tls_rpt" {
+  domain  = "example.com"
+  name    = "_smtp._tls"
+  type    = "TXT"
+  content = provider::porkbun::tls_rpt(["mailto:tls-reports@example.com"])
+}
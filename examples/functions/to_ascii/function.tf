@@ -0,0 +1,3 @@
+output "domain" {
+  value = provider::porkbun::to_ascii("münchen.de")
+}
@@ -0,0 +1,2 @@
+# Records are imported by their domain and Porkbun ID
+terraform import porkbun_dns_record.www example.com/123456
@@ -0,0 +1,208 @@
+package porkbuntest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// redactedIP replaces the IP address of the recording machine in the answers of the ping endpoint
+const redactedIP = "192.0.2.1"
+
+// Interaction is a request to the Porkbun API and its answer as a cassette stores them. The keys are removed from
+// the request body, so cassettes can be committed.
+type Interaction struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	Request  string `json:"request"`
+	Status   int    `json:"status"`
+	Response string `json:"response"`
+}
+
+// Cassette records the traffic of a test to the real Porkbun API into a file, or replays it from the file without
+// reaching the API. Use it as the transport of the Porkbun client.
+type Cassette struct {
+	// Seed is stored with the interactions so tests can replay the random values they used when recording
+	Seed         int64         `json:"seed"`
+	Interactions []Interaction `json:"interactions"`
+
+	file      string
+	next      http.RoundTripper
+	mu        sync.Mutex
+	used      []bool
+	recording bool
+}
+
+// NewRecorder returns a cassette that sends requests through next and records them. Save writes them to the file.
+func NewRecorder(file string, next http.RoundTripper, seed int64) *Cassette {
+	return &Cassette{Seed: seed, Interactions: []Interaction{}, file: file, next: next, recording: true}
+}
+
+// LoadCassette returns a cassette replaying the interactions recorded in the file
+func LoadCassette(file string) (*Cassette, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{file: file}
+	if err := json.Unmarshal(content, c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", file, err)
+	}
+	c.used = make([]bool, len(c.Interactions))
+
+	return c, nil
+}
+
+// Save writes the recorded interactions to the file of the cassette
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.file, append(content, '\n'), 0o644)
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	interaction := Interaction{
+		Method:   req.Method,
+		Endpoint: endpoint(req),
+		Request:  redactRequest(body),
+	}
+
+	if !c.recording {
+		return c.replay(req, interaction)
+	}
+
+	// A round tripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(response))
+
+	interaction.Status = resp.StatusCode
+	interaction.Response = redactResponse(response)
+
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, interaction)
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay answers with the first unused interaction for the same request. Requests repeated more often than they
+// were recorded get the last recorded answer. Requests that weren't recorded fail with a 400, which the provider
+// doesn't retry.
+func (c *Cassette) replay(req *http.Request, interaction Interaction) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	match := -1
+	for i, recorded := range c.Interactions {
+		if recorded.Method != interaction.Method || recorded.Endpoint != interaction.Endpoint || recorded.Request != interaction.Request {
+			continue
+		}
+
+		match = i
+		if !c.used[i] {
+			break
+		}
+	}
+
+	var recorded Interaction
+	if match < 0 {
+		message, _ := json.Marshal(map[string]string{
+			"status":  "ERROR",
+			"message": fmt.Sprintf("cassette %s has no interaction for %s %s with body %s, record it again", c.file, interaction.Method, interaction.Endpoint, interaction.Request),
+		})
+		recorded = Interaction{Status: http.StatusBadRequest, Response: string(message)}
+	} else {
+		c.used[match] = true
+		recorded = c.Interactions[match]
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(recorded.Response)),
+		ContentLength: int64(len(recorded.Response)),
+		Request:       req,
+	}, nil
+}
+
+// endpoint returns the path of the request after the API prefix, so cassettes don't depend on the base URL
+func endpoint(req *http.Request) string {
+	if i := strings.Index(req.URL.Path, apiPrefix); i >= 0 {
+		return req.URL.Path[i+len(apiPrefix):]
+	}
+
+	return strings.TrimPrefix(req.URL.Path, "/")
+}
+
+// redactRequest removes the keys from a request body and renders it with sorted fields
+func redactRequest(body []byte) string {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+
+	delete(decoded, "apikey")
+	delete(decoded, "secretapikey")
+
+	redacted, _ := json.Marshal(decoded)
+	return string(redacted)
+}
+
+// redactResponse replaces the IP address the ping endpoint answers with
+func redactResponse(body []byte) string {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+
+	if _, ok := decoded["yourIp"]; !ok {
+		return string(body)
+	}
+	decoded["yourIp"] = redactedIP
+
+	redacted, _ := json.Marshal(decoded)
+	return string(redacted)
+}
@@ -0,0 +1,74 @@
+package porkbuntest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nrdcg/porkbun"
+)
+
+func newCassetteClient(baseURL string, cassette *Cassette) *porkbun.Client {
+	client := porkbun.New(SecretKey, APIKey)
+	client.BaseURL, _ = url.Parse(baseURL)
+	client.HTTPClient = &http.Client{Transport: cassette}
+
+	return client
+}
+
+func Test_CassetteRecordAndReplay(t *testing.T) {
+	server := NewServer("example.com")
+	file := filepath.Join(t.TempDir(), "cassettes", "test.json")
+	ctx := context.Background()
+
+	recorder := NewRecorder(file, http.DefaultTransport, 42)
+	client := newCassetteClient(server.URL(), recorder)
+
+	if _, err := client.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Name: "www", Type: "A", Content: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), APIKey) || strings.Contains(string(content), SecretKey) || strings.Contains(string(content), "127.0.0.1") {
+		t.Errorf("expected the keys and the IP address to be redacted, got %s", content)
+	}
+
+	cassette, err := LoadCassette(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cassette.Seed != 42 {
+		t.Errorf("expected the seed to be stored, got %d", cassette.Seed)
+	}
+
+	// The base URL and the keys don't matter when replaying
+	client = newCassetteClient("https://api.porkbun.com/api/json/v3/", cassette)
+
+	for i := 0; i < 2; i++ {
+		replayed, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Name: "www", Type: "A", Content: "192.0.2.1"})
+		if err != nil || replayed != id {
+			t.Errorf("expected the recorded ID %d, got %d: %v", id, replayed, err)
+		}
+	}
+
+	if _, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Name: "www", Type: "A", Content: "192.0.2.2"}); err == nil {
+		t.Error("expected a request that wasn't recorded to fail")
+	}
+}
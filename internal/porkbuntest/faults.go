@@ -0,0 +1,85 @@
+package porkbuntest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+)
+
+// FaultKind is a way the real Porkbun API fails
+type FaultKind int
+
+const (
+	// FaultHTTPError answers with Status and Message without processing the request
+	FaultHTTPError FaultKind = iota
+
+	// FaultRateLimit answers with a 503 and a Retry-After of Delay without processing the request, like Porkbun
+	// does when it rate limits
+	FaultRateLimit
+
+	// FaultErrorStatus answers with a 200 and an ERROR status with Message without processing the request
+	FaultErrorStatus
+
+	// FaultTruncated processes the request, but cuts the JSON response short
+	FaultTruncated
+
+	// FaultSlow processes the request, but only answers after Delay
+	FaultSlow
+
+	// FaultFailAfterApply processes the request, but answers with a 500 as if it failed
+	FaultFailAfterApply
+)
+
+// Fault scripts how the server answers the next requests to an endpoint
+type Fault struct {
+	// Endpoint is matched as a prefix of the path after the base URL, e.g. `dns/create`
+	Endpoint string
+	Kind     FaultKind
+	// Count is how many requests the fault applies to, at least one
+	Count   int
+	Status  int
+	Message string
+	Delay   time.Duration
+}
+
+// processes reports whether the request changes records before the fault applies to the response
+func (k FaultKind) processes() bool {
+	switch k {
+	case FaultTruncated, FaultSlow, FaultFailAfterApply:
+		return true
+	}
+
+	return false
+}
+
+// respond writes the faulty response, response is the regular response of faults that process the request
+func (f *Fault) respond(w http.ResponseWriter, response *httptest.ResponseRecorder) {
+	switch f.Kind {
+	case FaultHTTPError:
+		writeError(w, f.Status, f.Message)
+	case FaultRateLimit:
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.Delay.Seconds())))
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable")
+	case FaultErrorStatus:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ERROR","message":"` + f.Message + `"}`))
+	case FaultTruncated:
+		body := response.Body.Bytes()
+		copyResponse(w, response, body[:len(body)/2])
+	case FaultSlow:
+		time.Sleep(f.Delay)
+		copyResponse(w, response, response.Body.Bytes())
+	case FaultFailAfterApply:
+		writeError(w, http.StatusInternalServerError, "Internal Server Error")
+	}
+}
+
+func copyResponse(w http.ResponseWriter, response *httptest.ResponseRecorder, body []byte) {
+	for key, values := range response.Header() {
+		w.Header()[key] = values
+	}
+
+	w.WriteHeader(response.Code)
+	_, _ = w.Write(body)
+}
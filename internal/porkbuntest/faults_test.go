@@ -0,0 +1,65 @@
+package porkbuntest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nrdcg/porkbun"
+)
+
+func Test_ServerFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   Fault
+		status  int
+		created bool
+	}{
+		{"rate limit", Fault{Kind: FaultRateLimit, Delay: time.Second}, http.StatusServiceUnavailable, false},
+		{"error status", Fault{Kind: FaultErrorStatus, Message: "Something went wrong."}, 0, false},
+		{"truncated", Fault{Kind: FaultTruncated}, 0, true},
+		{"slow", Fault{Kind: FaultSlow, Delay: 50 * time.Millisecond}, 0, true},
+		{"fail after apply", Fault{Kind: FaultFailAfterApply}, http.StatusInternalServerError, true},
+	}
+
+	ctx := context.Background()
+	record := porkbun.Record{Name: "www", Type: "A", Content: "192.0.2.1"}
+
+	for _, tc := range tests {
+		server := NewServer("example.com")
+		client := newClient(t, server, APIKey)
+
+		tc.fault.Endpoint = "dns/create"
+		server.Script(tc.fault)
+
+		start := time.Now()
+		_, err := client.CreateRecord(ctx, "example.com", record)
+
+		var serverErr *porkbun.ServerError
+		switch {
+		case tc.fault.Kind == FaultSlow && (err != nil || time.Since(start) < tc.fault.Delay):
+			t.Errorf("%s: expected a delayed success, got %v after %s", tc.name, err, time.Since(start))
+		case tc.fault.Kind != FaultSlow && err == nil:
+			t.Errorf("%s: expected an error", tc.name)
+		case tc.status != 0 && (!errors.As(err, &serverErr) || serverErr.StatusCode != tc.status):
+			t.Errorf("%s: expected status %d, got %v", tc.name, tc.status, err)
+		}
+
+		if created := len(server.Records("example.com")) == 1; created != tc.created {
+			t.Errorf("%s: expected the record to be created: %v", tc.name, tc.created)
+		}
+
+		if pending := server.PendingFaults(); pending != 0 {
+			t.Errorf("%s: expected the fault to be used, %d are left", tc.name, pending)
+		}
+
+		// The fault only applies to the next request
+		if _, err := client.CreateRecord(ctx, "example.com", record); err != nil {
+			t.Errorf("%s: expected the next request to succeed, got %v", tc.name, err)
+		}
+
+		server.Close()
+	}
+}
@@ -0,0 +1,391 @@
+// Package porkbuntest provides an in-process fake of the Porkbun v3 JSON API for tests.
+//
+// The fake keeps the records of each zone in memory, assigns IDs like Porkbun does and checks the API keys of every
+// request. Faults like rate limiting, errors and slow or broken responses can be scripted for the next requests to
+// an endpoint. A Cassette records the traffic of a test to the real API, so it can be replayed without keys.
+package porkbuntest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// APIKey and SecretKey are the keys the server accepts unless others are given to NewServer
+	APIKey    = "pk1_porkbuntest"
+	SecretKey = "sk1_porkbuntest"
+
+	apiPrefix = "/api/json/v3/"
+
+	// firstRecordID is the ID of the first record created, Porkbun IDs are large numbers
+	firstRecordID = 100000000
+)
+
+// Record is a DNS record as the retrieve endpoint returns it, with the full name of the record
+type Record struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl"`
+	Prio    string `json:"prio"`
+	Notes   string `json:"notes"`
+}
+
+// Request is a request the server received, without the keys
+type Request struct {
+	Method   string
+	Endpoint string
+	Body     map[string]interface{}
+}
+
+// Server is a fake Porkbun API. Use URL as the base URL of the client.
+type Server struct {
+	*httptest.Server
+
+	apiKey    string
+	secretKey string
+
+	mu       sync.Mutex
+	nextID   int
+	zones    map[string][]Record
+	requests []Request
+	faults   []*Fault
+}
+
+// NewServer starts a fake Porkbun API serving the given zones. It accepts APIKey and SecretKey.
+func NewServer(domains ...string) *Server {
+	return NewServerWithKeys(APIKey, SecretKey, domains...)
+}
+
+// NewServerWithKeys starts a fake Porkbun API serving the given zones that accepts the given keys
+func NewServerWithKeys(apiKey string, secretKey string, domains ...string) *Server {
+	s := &Server{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		nextID:    firstRecordID,
+		zones:     map[string][]Record{},
+	}
+
+	for _, domain := range domains {
+		s.AddZone(domain)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// URL returns the base URL of the API, e.g. http://127.0.0.1:1234/api/json/v3/
+func (s *Server) URL() string {
+	return s.Server.URL + apiPrefix
+}
+
+// AddZone adds an empty zone for the domain, requests for unknown domains fail like they do for domains that
+// aren't in the account
+func (s *Server) AddZone(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domain = strings.ToLower(domain)
+	if _, ok := s.zones[domain]; !ok {
+		s.zones[domain] = []Record{}
+	}
+}
+
+// AddRecord adds a record to the zone of the domain and returns its ID. The name of the record is relative to
+// the domain like in a create request.
+func (s *Server) AddRecord(domain string, record Record) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domain = strings.ToLower(domain)
+	return s.addRecord(domain, record)
+}
+
+// Records returns a copy of the records of the zone of the domain
+func (s *Server) Records(domain string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Record{}, s.zones[strings.ToLower(domain)]...)
+}
+
+// Requests returns the requests the server received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// Script adds faults for the next requests. Each request uses the first remaining fault matching its endpoint.
+func (s *Server) Script(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, fault := range faults {
+		fault := fault
+		if fault.Count < 1 {
+			fault.Count = 1
+		}
+		s.faults = append(s.faults, &fault)
+	}
+}
+
+// PendingFaults returns how many scripted faults haven't been used yet
+func (s *Server) PendingFaults() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := 0
+	for _, fault := range s.faults {
+		pending += fault.Count
+	}
+
+	return pending
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+	var body map[string]interface{}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&body) != nil {
+		writeError(w, http.StatusBadRequest, "All requests must be POST requests with a JSON body.")
+		return
+	}
+
+	apiKey, _ := body["apikey"].(string)
+	secretKey, _ := body["secretapikey"].(string)
+	delete(body, "apikey")
+	delete(body, "secretapikey")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Endpoint: endpoint, Body: body})
+	fault := s.nextFault(endpoint)
+
+	if fault != nil && !fault.Kind.processes() {
+		s.mu.Unlock()
+		fault.respond(w, nil)
+		return
+	}
+
+	// Faults that process the request change the response after the records were changed
+	response := httptest.NewRecorder()
+	s.handle(response, endpoint, apiKey, secretKey, body)
+	s.mu.Unlock()
+
+	if fault != nil {
+		fault.respond(w, response)
+		return
+	}
+
+	copyResponse(w, response, response.Body.Bytes())
+}
+
+func (s *Server) handle(w http.ResponseWriter, endpoint string, apiKey string, secretKey string, body map[string]interface{}) {
+	if apiKey != s.apiKey || secretKey != s.secretKey {
+		writeError(w, http.StatusBadRequest, "Invalid API key. (002)")
+		return
+	}
+
+	parts := strings.Split(endpoint, "/")
+	switch {
+	case endpoint == "ping":
+		writeJSON(w, map[string]interface{}{"status": "SUCCESS", "yourIp": "127.0.0.1"})
+	case len(parts) == 3 && parts[0] == "dns" && parts[1] == "create":
+		s.create(w, parts[2], body)
+	case len(parts) == 4 && parts[0] == "dns" && parts[1] == "edit":
+		s.edit(w, parts[2], parts[3], body)
+	case len(parts) == 4 && parts[0] == "dns" && parts[1] == "delete":
+		s.delete(w, parts[2], parts[3])
+	case (len(parts) == 3 || len(parts) == 4) && parts[0] == "dns" && parts[1] == "retrieve":
+		s.retrieve(w, parts[2], parts[3:])
+	default:
+		writeError(w, http.StatusNotFound, "Endpoint not found.")
+	}
+}
+
+func (s *Server) nextFault(endpoint string) *Fault {
+	for i, fault := range s.faults {
+		if !strings.HasPrefix(endpoint, fault.Endpoint) {
+			continue
+		}
+
+		fault.Count--
+		if fault.Count <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+
+		return fault
+	}
+
+	return nil
+}
+
+func (s *Server) create(w http.ResponseWriter, domain string, body map[string]interface{}) {
+	domain = strings.ToLower(domain)
+	if _, ok := s.zones[domain]; !ok {
+		writeError(w, http.StatusBadRequest, "Invalid domain.")
+		return
+	}
+
+	record, message := recordFromBody(body)
+	if message != "" {
+		writeError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	id, _ := strconv.Atoi(s.addRecord(domain, record))
+	writeJSON(w, map[string]interface{}{"status": "SUCCESS", "id": id})
+}
+
+func (s *Server) edit(w http.ResponseWriter, domain string, id string, body map[string]interface{}) {
+	domain = strings.ToLower(domain)
+	index := s.recordIndex(domain, id)
+	if index < 0 {
+		writeError(w, http.StatusBadRequest, "Edit error: We were unable to edit the DNS record.")
+		return
+	}
+
+	record, message := recordFromBody(body)
+	if message != "" {
+		writeError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	record.ID = id
+	record.Name = fullName(record.Name, domain)
+	s.zones[domain][index] = record
+
+	writeJSON(w, map[string]interface{}{"status": "SUCCESS"})
+}
+
+func (s *Server) delete(w http.ResponseWriter, domain string, id string) {
+	domain = strings.ToLower(domain)
+	index := s.recordIndex(domain, id)
+	if index < 0 {
+		writeError(w, http.StatusBadRequest, "Delete error: Invalid record ID.")
+		return
+	}
+
+	s.zones[domain] = append(s.zones[domain][:index], s.zones[domain][index+1:]...)
+
+	writeJSON(w, map[string]interface{}{"status": "SUCCESS"})
+}
+
+func (s *Server) retrieve(w http.ResponseWriter, domain string, id []string) {
+	domain = strings.ToLower(domain)
+	records, ok := s.zones[domain]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid domain.")
+		return
+	}
+
+	if len(id) == 1 {
+		records = []Record{}
+		if index := s.recordIndex(domain, id[0]); index >= 0 {
+			records = append(records, s.zones[domain][index])
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{"status": "SUCCESS", "records": records})
+}
+
+func (s *Server) addRecord(domain string, record Record) string {
+	if _, ok := s.zones[domain]; !ok {
+		s.zones[domain] = []Record{}
+	}
+
+	s.nextID++
+	record.ID = strconv.Itoa(s.nextID)
+	record.Name = fullName(record.Name, domain)
+	if record.TTL == "" {
+		record.TTL = "600"
+	}
+	if record.Prio == "" {
+		record.Prio = "0"
+	}
+
+	s.zones[domain] = append(s.zones[domain], record)
+	sort.SliceStable(s.zones[domain], func(i, j int) bool { return s.zones[domain][i].Name < s.zones[domain][j].Name })
+
+	return record.ID
+}
+
+func (s *Server) recordIndex(domain string, id string) int {
+	for i, record := range s.zones[domain] {
+		if record.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// recordFromBody reads the record of a create or edit request, returning the error message Porkbun would send
+// for an invalid one
+func recordFromBody(body map[string]interface{}) (Record, string) {
+	str := func(key string) string {
+		value, _ := body[key].(string)
+		return value
+	}
+
+	record := Record{
+		Name:    str("name"),
+		Type:    strings.ToUpper(str("type")),
+		Content: str("content"),
+		TTL:     str("ttl"),
+		Prio:    str("prio"),
+		Notes:   str("notes"),
+	}
+
+	switch {
+	case record.Type == "":
+		return record, "Invalid type."
+	case record.Content == "":
+		return record, "Invalid content."
+	}
+
+	if record.TTL != "" {
+		if ttl, err := strconv.Atoi(record.TTL); err != nil || ttl < 600 {
+			return record, fmt.Sprintf("Invalid TTL %q, the minimum is 600.", record.TTL)
+		}
+	}
+
+	if record.TTL == "" {
+		record.TTL = "600"
+	}
+	if record.Prio == "" {
+		record.Prio = "0"
+	}
+
+	return record, ""
+}
+
+// fullName returns the name of a record as Porkbun returns it, including the domain
+func fullName(name string, domain string) string {
+	if name == "" {
+		return domain
+	}
+
+	return strings.ToLower(name) + "." + domain
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeError answers like Porkbun does for a failed request. The body of a 503 is ignored by clients.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "ERROR", "message": message})
+}
@@ -0,0 +1,101 @@
+package porkbuntest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/nrdcg/porkbun"
+)
+
+func newClient(t *testing.T, server *Server, apiKey string) *porkbun.Client {
+	t.Helper()
+
+	client := porkbun.New(SecretKey, apiKey)
+	client.BaseURL, _ = url.Parse(server.URL())
+
+	return client
+}
+
+func Test_ServerRecordLifecycle(t *testing.T) {
+	server := NewServer("example.com")
+	defer server.Close()
+
+	ctx := context.Background()
+	client := newClient(t, server, APIKey)
+
+	id, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Name: "WWW", Type: "a", Content: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := client.RetrieveRecords(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := porkbun.Record{ID: strconv.Itoa(id), Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"}
+	if len(records) != 1 || records[0] != expected {
+		t.Fatalf("expected %v, got %v", expected, records)
+	}
+
+	if err := client.EditRecord(ctx, "example.com", id, porkbun.Record{Type: "A", Content: "192.0.2.2", TTL: "900"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if records := server.Records("example.com"); records[0].Name != "example.com" || records[0].Content != "192.0.2.2" || records[0].TTL != "900" {
+		t.Errorf("unexpected record after edit %v", records[0])
+	}
+
+	if err := client.DeleteRecord(ctx, "example.com", id); err != nil {
+		t.Fatal(err)
+	}
+
+	if records := server.Records("example.com"); len(records) != 0 {
+		t.Errorf("expected the record to be deleted, got %v", records)
+	}
+
+	if err := client.DeleteRecord(ctx, "example.com", id); err == nil {
+		t.Error("expected deleting an unknown record to fail")
+	}
+}
+
+func Test_ServerErrors(t *testing.T) {
+	server := NewServer("example.com")
+	defer server.Close()
+
+	ctx := context.Background()
+
+	if _, err := newClient(t, server, "pk1_wrong").Ping(ctx); err == nil {
+		t.Error("expected wrong keys to be rejected")
+	}
+
+	client := newClient(t, server, APIKey)
+
+	if _, err := client.RetrieveRecords(ctx, "example.net"); err == nil {
+		t.Error("expected an unknown domain to be rejected")
+	}
+
+	if _, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Type: "A", Content: "192.0.2.1", TTL: "60"}); err == nil {
+		t.Error("expected a TTL below 600 to be rejected")
+	}
+
+	server.Script(Fault{Endpoint: "dns/create", Kind: FaultHTTPError, Status: http.StatusServiceUnavailable, Count: 2})
+	for i := 0; i < 2; i++ {
+		var serverErr *porkbun.ServerError
+		if _, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Type: "A", Content: "192.0.2.1"}); !errors.As(err, &serverErr) || serverErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected the injected error, got %v", err)
+		}
+	}
+
+	if _, err := client.CreateRecord(ctx, "example.com", porkbun.Record{Type: "A", Content: "192.0.2.1"}); err != nil {
+		t.Errorf("expected the create to succeed after the injected errors, got %v", err)
+	}
+
+	if records := server.Records("example.com"); len(records) != 1 {
+		t.Errorf("expected a single record, got %v", records)
+	}
+}
@@ -0,0 +1,525 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrdcg/porkbun"
+)
+
+const (
+	defaultCredentialsFile = "~/.config/porkbun/credentials"
+	defaultProfile         = "default"
+
+	// Prefixes of the keys Porkbun generates
+	apiKeyPrefix    = "pk1_"
+	secretKeyPrefix = "sk1_"
+
+	// credentialProcessTimeout is how long a credential_process may run, it may have to wait for a secret manager
+	credentialProcessTimeout = 1 * time.Minute
+)
+
+// credentials are the keys used to authenticate with Porkbun along with where they were found
+type credentials struct {
+	apiKey          string
+	secretKey       string
+	apiKeySource    string
+	secretKeySource string
+}
+
+// source describes where the keys were found
+func (c credentials) source() string {
+	if c.apiKeySource == c.secretKeySource {
+		return c.apiKeySource
+	}
+
+	return fmt.Sprintf("%s and %s", c.apiKeySource, c.secretKeySource)
+}
+
+// credentialsProfile is a named profile of the credentials file
+type credentialsProfile struct {
+	apiKey            string
+	secretKey         string
+	credentialProcess string
+	source            string
+	// explicit is set when the profile was selected with the profile attribute or PORKBUN_PROFILE
+	explicit bool
+}
+
+// credentialProcessOutput is what a credential_process prints to stdout
+type credentialProcessOutput struct {
+	ApiKey    string `json:"api_key"`
+	SecretKey string `json:"secret_key"`
+}
+
+// credentialProcessResults caches the keys returned by each credential_process for the life of the plugin process,
+// so the command isn't run again for every provider configuration
+var credentialProcessResults = struct {
+	sync.Mutex
+	locks   map[string]*sync.Mutex
+	outputs map[string]credentialProcessOutput
+}{locks: map[string]*sync.Mutex{}, outputs: map[string]credentialProcessOutput{}}
+
+// resolveCredentials finds the API and secret key. Each key is taken from the provider configuration, or else from
+// its environment variable. When the configuration sets neither key, the credential_process attribute or an
+// explicitly selected profile of the credentials file take precedence over the environment, and the default profile
+// is used when the environment doesn't set a key either. A credential_process or profile has to provide both keys,
+// and a profile with a credential_process takes the keys from the command.
+func resolveCredentials(ctx context.Context, data PorkbunProviderModel) (credentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var creds credentials
+	var checked []string
+
+	// A key that is already set is kept, so the configuration and the environment can each set one of the keys
+	use := func(apiKey string, secretKey string, apiKeySource string, secretKeySource string) {
+		checked = append(checked, apiKeySource)
+		if secretKeySource != apiKeySource {
+			checked = append(checked, secretKeySource)
+		}
+
+		if creds.apiKey == "" && apiKey != "" {
+			creds.apiKey, creds.apiKeySource = apiKey, apiKeySource
+		}
+		if creds.secretKey == "" && secretKey != "" {
+			creds.secretKey, creds.secretKeySource = secretKey, secretKeySource
+		}
+	}
+	found := func() bool {
+		return creds.apiKey != "" || creds.secretKey != ""
+	}
+
+	// The keys of a credential_process or profile belong together, they are never combined with other sources
+	usePair := func(apiKey string, secretKey string, source string) {
+		switch {
+		case apiKey == "" && secretKey != "":
+			diags.AddError(
+				"Unable to find api_key",
+				fmt.Sprintf("%s sets the secret_key but not the api_key, both keys must come from the same source", source),
+			)
+		case apiKey != "" && secretKey == "":
+			diags.AddError(
+				"Unable to find secret_key",
+				fmt.Sprintf("%s sets the api_key but not the secret_key, both keys must come from the same source", source),
+			)
+		default:
+			use(apiKey, secretKey, source, source)
+		}
+	}
+
+	useProcess := func(command string, source string) {
+		output, err := runCredentialProcess(ctx, command)
+		if err != nil {
+			diags.AddAttributeError(path.Root("credential_process"), "Unable to run credential_process", fmt.Sprintf("%s: %s", source, err))
+			return
+		}
+
+		usePair(output.ApiKey, output.SecretKey, source)
+	}
+
+	useProfile := func(profile *credentialsProfile) {
+		if profile.credentialProcess != "" {
+			useProcess(profile.credentialProcess, "the credential_process of "+profile.source)
+			return
+		}
+
+		usePair(profile.apiKey, profile.secretKey, profile.source)
+	}
+
+	use(data.ApiKey.ValueString(), data.SecretKey.ValueString(), "the provider configuration", "the provider configuration")
+
+	if !found() && !data.CredentialProcess.IsNull() {
+		useProcess(data.CredentialProcess.ValueString(), "the credential_process attribute")
+	}
+
+	var profile *credentialsProfile
+	if !found() && !diags.HasError() {
+		var d diag.Diagnostics
+		profile, d = loadCredentialsProfile(data)
+		diags.Append(d...)
+
+		if profile != nil && profile.explicit && !diags.HasError() {
+			useProfile(profile)
+		}
+	}
+
+	if diags.HasError() {
+		return creds, diags
+	}
+
+	if creds.apiKey == "" || creds.secretKey == "" {
+		use(os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_KEY"), "the PORKBUN_API_KEY environment variable", "the PORKBUN_SECRET_KEY environment variable")
+	}
+
+	if !found() && profile != nil && !profile.explicit {
+		useProfile(profile)
+	}
+
+	if diags.HasError() {
+		return creds, diags
+	}
+
+	if creds.apiKey == "" {
+		// Error vs warning - empty value must stop execution
+		diags.AddError(
+			"Unable to find api_key",
+			fmt.Sprintf("api_key cannot be an empty string, checked %s", strings.Join(checked, ", ")),
+		)
+	}
+	if creds.secretKey == "" {
+		diags.AddError(
+			"Unable to find secret_key",
+			fmt.Sprintf("secret_key cannot be an empty string, checked %s", strings.Join(checked, ", ")),
+		)
+	}
+
+	if diags.HasError() {
+		return creds, diags
+	}
+
+	tflog.Debug(ctx, "Using Porkbun credentials", map[string]interface{}{
+		"api_key_source":    creds.apiKeySource,
+		"secret_key_source": creds.secretKeySource,
+	})
+
+	return creds, diags
+}
+
+// validateKeyFormat checks that the keys look like Porkbun keys, catching swapped or truncated keys without a request
+func validateKeyFormat(creds credentials) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !strings.HasPrefix(creds.apiKey, apiKeyPrefix) {
+		diags.AddError(
+			"Invalid api_key",
+			fmt.Sprintf("The api_key from %s doesn't look like a Porkbun API key, which starts with %s", creds.apiKeySource, apiKeyPrefix),
+		)
+	}
+
+	if !strings.HasPrefix(creds.secretKey, secretKeyPrefix) {
+		diags.AddError(
+			"Invalid secret_key",
+			fmt.Sprintf("The secret_key from %s doesn't look like a Porkbun secret key, which starts with %s", creds.secretKeySource, secretKeyPrefix),
+		)
+	}
+
+	return diags
+}
+
+// pingCredentials checks that Porkbun accepts the keys using the ping endpoint
+func pingCredentials(ctx context.Context, client *porkbun.Client, creds credentials) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	ip, err := client.Ping(ctx)
+	if err == nil {
+		tflog.Debug(ctx, "Validated Porkbun credentials", map[string]interface{}{"ip": ip})
+		return diags
+	}
+
+	// Porkbun answers with either an ERROR status or a 400 with the status in the body
+	if strings.Contains(strings.ToLower(err.Error()), "invalid api key") {
+		diags.AddError(
+			"Invalid Porkbun credentials",
+			fmt.Sprintf(
+				"Porkbun rejected the keys from %s: %s\n\n"+
+					"Check that both keys belong to the same API key pair and haven't been revoked.",
+				creds.source(), err,
+			),
+		)
+		return diags
+	}
+
+	diags.AddError(
+		"Unable to validate Porkbun credentials",
+		fmt.Sprintf("Calling the ping endpoint failed: %s\n\nSet validate_credentials to false to skip this check.", err),
+	)
+
+	return diags
+}
+
+// loadCredentialsProfile reads the selected profile from the credentials file. A missing file is only an error
+// when the file or profile were chosen explicitly, otherwise no profile is returned.
+func loadCredentialsProfile(data PorkbunProviderModel) (*credentialsProfile, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	file, fileSet := defaultCredentialsFile, false
+	if !data.CredentialsFile.IsNull() {
+		file, fileSet = data.CredentialsFile.ValueString(), true
+	} else if env, ok := os.LookupEnv("PORKBUN_CREDENTIALS_FILE"); ok {
+		file, fileSet = env, true
+	}
+
+	name, profileSet := defaultProfile, false
+	if !data.Profile.IsNull() {
+		name, profileSet = data.Profile.ValueString(), true
+	} else if env, ok := os.LookupEnv("PORKBUN_PROFILE"); ok {
+		name, profileSet = env, true
+	}
+
+	file, err := expandHome(file)
+	if err != nil {
+		diags.AddAttributeError(path.Root("credentials_file"), "Unable to read credentials file", err.Error())
+		return nil, diags
+	}
+
+	info, err := os.Stat(file)
+	if errors.Is(err, fs.ErrNotExist) && !fileSet && !profileSet {
+		return nil, diags
+	}
+	if err != nil {
+		diags.AddAttributeError(path.Root("credentials_file"), "Unable to read credentials file", err.Error())
+		return nil, diags
+	}
+
+	// Windows doesn't have Unix permission bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		diags.AddAttributeWarning(
+			path.Root("credentials_file"),
+			"Insecure credentials file permissions",
+			fmt.Sprintf("%s can be read by other users (mode %04o), restrict it with `chmod 600 %s`", file, info.Mode().Perm(), file),
+		)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		diags.AddAttributeError(path.Root("credentials_file"), "Unable to read credentials file", err.Error())
+		return nil, diags
+	}
+
+	profiles, err := parseCredentialsFile(content)
+	if err != nil {
+		diags.AddAttributeError(path.Root("credentials_file"), "Unable to parse credentials file", fmt.Sprintf("%s: %s", file, err))
+		return nil, diags
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unable to find profile",
+			fmt.Sprintf("profile %q does not exist in %s, available profiles are: %s", name, file, strings.Join(names, ", ")),
+		)
+		return nil, diags
+	}
+
+	return &credentialsProfile{
+		apiKey:            profile["api_key"],
+		secretKey:         profile["secret_key"],
+		credentialProcess: profile["credential_process"],
+		source:            fmt.Sprintf("profile %q of %s", name, file),
+		explicit:          profileSet,
+	}, diags
+}
+
+// runCredentialProcess runs the command and parses the keys it prints, e.g.
+//
+//	{"api_key": "pk1_...", "secret_key": "sk1_..."}
+//
+// The output contains the keys, so it is never logged or included in errors. Neither is stderr, a failing
+// command may print what it read.
+func runCredentialProcess(ctx context.Context, command string) (credentialProcessOutput, error) {
+	credentialProcessResults.Lock()
+	lock, ok := credentialProcessResults.locks[command]
+	if !ok {
+		lock = &sync.Mutex{}
+		credentialProcessResults.locks[command] = lock
+	}
+	credentialProcessResults.Unlock()
+
+	// Configurations using the same command wait for its first run, other commands run concurrently
+	lock.Lock()
+	defer lock.Unlock()
+
+	credentialProcessResults.Lock()
+	output, ok := credentialProcessResults.outputs[command]
+	credentialProcessResults.Unlock()
+
+	if ok {
+		tflog.Debug(ctx, "Using cached credential_process output")
+		return output, nil
+	}
+
+	args, err := splitCommand(command)
+	if err != nil {
+		return credentialProcessOutput{}, err
+	}
+	if len(args) == 0 {
+		return credentialProcessOutput{}, fmt.Errorf("the command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+
+	tflog.Debug(ctx, "Running credential_process", map[string]interface{}{"command": args[0]})
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return credentialProcessOutput{}, fmt.Errorf("%s timed out after %s", args[0], credentialProcessTimeout)
+		}
+		return credentialProcessOutput{}, fmt.Errorf("%s failed: %s", args[0], err)
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		// Don't include the output in the error, it may contain the keys
+		return credentialProcessOutput{}, fmt.Errorf("%s did not print valid JSON with api_key and secret_key", args[0])
+	}
+
+	credentialProcessResults.Lock()
+	credentialProcessResults.outputs[command] = output
+	credentialProcessResults.Unlock()
+
+	return output, nil
+}
+
+// splitCommand splits a command line into its arguments like a POSIX shell does, supporting single and double
+// quotes and backslash escapes, but no expansions
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\' && (quote == 0 || (i+1 < len(runes) && strings.ContainsRune(`"\\$`+"`", runes[i+1]))):
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("command ends with an unfinished escape")
+			}
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("command has an unterminated %c quote", quote)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// parseCredentialsFile parses the profiles of a credentials file, either as JSON
+//
+//	{"default": {"api_key": "pk1_...", "secret_key": "sk1_..."}}
+//
+// or in INI format
+//
+//	[default]
+//	api_key = pk1_...
+//	secret_key = sk1_...
+func parseCredentialsFile(content []byte) (map[string]map[string]string, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		var profiles map[string]map[string]string
+		if err := json.Unmarshal(content, &profiles); err != nil {
+			return nil, err
+		}
+		return profiles, nil
+	}
+
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+		default:
+			key, value, ok := strings.Cut(text, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected `key = value`", line)
+			}
+			if current == nil {
+				return nil, fmt.Errorf("line %d: %s is not part of a [profile]", line, strings.TrimSpace(key))
+			}
+			current[strings.TrimSpace(key)] = unquoteValue(strings.TrimSpace(value))
+		}
+	}
+
+	return profiles, scanner.Err()
+}
+
+// unquoteValue removes the quotes around a whole value of the credentials file
+func unquoteValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+func expandHome(file string) (string, error) {
+	if file != "~" && !strings.HasPrefix(file, "~/") {
+		return file, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(file, "~")), nil
+}
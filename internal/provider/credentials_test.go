@@ -0,0 +1,366 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nrdcg/porkbun"
+)
+
+func writeCredentialsFile(t *testing.T, content string, mode os.FileMode) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(file, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile doesn't change the mode of the file beyond the umask
+	if err := os.Chmod(file, mode); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func emptyProviderModel() PorkbunProviderModel {
+	return PorkbunProviderModel{
+		ApiKey:          types.StringNull(),
+		SecretKey:       types.StringNull(),
+		CredentialsFile: types.StringNull(),
+		Profile:         types.StringNull(),
+	}
+}
+
+func Test_ParseCredentialsFile(t *testing.T) {
+	ini := `
+# Porkbun accounts
+[default]
+api_key = pk1_default
+secret_key = "sk1_default"
+
+[work]
+api_key=pk1_work
+; no secret key
+`
+	json := `{"default": {"api_key": "pk1_default", "secret_key": "sk1_default"}, "work": {"api_key": "pk1_work"}}`
+
+	for name, content := range map[string]string{"ini": ini, "json": json} {
+		profiles, err := parseCredentialsFile([]byte(content))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		if profiles["default"]["api_key"] != "pk1_default" || profiles["default"]["secret_key"] != "sk1_default" {
+			t.Errorf("%s: unexpected default profile %v", name, profiles["default"])
+		}
+
+		if profiles["work"]["api_key"] != "pk1_work" || profiles["work"]["secret_key"] != "" {
+			t.Errorf("%s: unexpected work profile %v", name, profiles["work"])
+		}
+	}
+
+	if _, err := parseCredentialsFile([]byte("api_key = pk1_orphan")); err == nil {
+		t.Error("expected a key outside of a profile to be an error")
+	}
+}
+
+func Test_ResolveCredentialsPrecedence(t *testing.T) {
+	file := writeCredentialsFile(t, "[default]\napi_key = pk1_default\nsecret_key = sk1_default\n[work]\napi_key = pk1_file\nsecret_key = sk1_file\n", 0o600)
+	t.Setenv("PORKBUN_API_KEY", "pk1_env")
+	t.Setenv("PORKBUN_SECRET_KEY", "sk1_env")
+
+	data := emptyProviderModel()
+	data.CredentialsFile = types.StringValue(file)
+
+	creds, diags := resolveCredentials(context.Background(), data)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if creds.apiKey != "pk1_env" || creds.secretKey != "sk1_env" {
+		t.Errorf("expected the keys from the environment over the default profile, got %q and %q from %s", creds.apiKey, creds.secretKey, creds.source())
+	}
+
+	data.Profile = types.StringValue("work")
+	creds, diags = resolveCredentials(context.Background(), data)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if creds.apiKey != "pk1_file" || creds.secretKey != "sk1_file" || creds.source() != `profile "work" of `+file {
+		t.Errorf("expected the keys from the selected profile, got %q and %q from %s", creds.apiKey, creds.secretKey, creds.source())
+	}
+
+	data.ApiKey = types.StringValue("pk1_config")
+	data.SecretKey = types.StringValue("sk1_config")
+	creds, _ = resolveCredentials(context.Background(), data)
+	if creds.apiKey != "pk1_config" || creds.secretKey != "sk1_config" || creds.source() != "the provider configuration" {
+		t.Errorf("expected the keys from the configuration, got %q and %q from %s", creds.apiKey, creds.secretKey, creds.source())
+	}
+}
+
+func Test_ResolveCredentialsCombiningSources(t *testing.T) {
+	file := writeCredentialsFile(t, "[default]\napi_key = pk1_default\nsecret_key = sk1_default\n", 0o600)
+	t.Setenv("PORKBUN_API_KEY", "")
+	t.Setenv("PORKBUN_SECRET_KEY", "sk1_env")
+
+	data := emptyProviderModel()
+	data.CredentialsFile = types.StringValue(file)
+
+	// The api key of the profile must not be paired with the secret key of the environment
+	_, diags := resolveCredentials(context.Background(), data)
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "Unable to find api_key" {
+		t.Errorf("expected the api key to be missing from the environment, got %v", diags)
+	}
+
+	// The configuration and the environment can each set one of the keys
+	data.ApiKey = types.StringValue("pk1_config")
+	creds, diags := resolveCredentials(context.Background(), data)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if creds.apiKey != "pk1_config" || creds.secretKey != "sk1_env" || creds.source() != "the provider configuration and the PORKBUN_SECRET_KEY environment variable" {
+		t.Errorf("expected the api key from the configuration and the secret key from the environment, got %q and %q from %s", creds.apiKey, creds.secretKey, creds.source())
+	}
+
+	// The secret key of the default profile must not be paired with the api key of the configuration
+	t.Setenv("PORKBUN_SECRET_KEY", "")
+	_, diags = resolveCredentials(context.Background(), data)
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "Unable to find secret_key" {
+		t.Errorf("expected the secret key to be missing, got %v", diags)
+	}
+
+	// A profile has to provide both keys
+	data.ApiKey = types.StringNull()
+	data.CredentialsFile = types.StringValue(writeCredentialsFile(t, "[default]\napi_key = pk1_default\n", 0o600))
+	_, diags = resolveCredentials(context.Background(), data)
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags[0].Detail(), "both keys must come from the same source") {
+		t.Errorf("expected an incomplete profile to be an error, got %v", diags)
+	}
+}
+
+func Test_ResolveCredentialsProfileErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PORKBUN_API_KEY", "")
+	t.Setenv("PORKBUN_SECRET_KEY", "")
+
+	// Without a credentials file only the missing keys are reported
+	_, diags := resolveCredentials(context.Background(), emptyProviderModel())
+	if diags.ErrorsCount() != 2 {
+		t.Errorf("expected both keys to be missing, got %v", diags)
+	}
+
+	t.Setenv("PORKBUN_PROFILE", "personal")
+	_, diags = resolveCredentials(context.Background(), emptyProviderModel())
+	if !diags.HasError() || diags[0].Summary() != "Unable to read credentials file" {
+		t.Errorf("expected the missing credentials file to be an error for an explicit profile, got %v", diags)
+	}
+
+	data := emptyProviderModel()
+	data.CredentialsFile = types.StringValue(writeCredentialsFile(t, "[default]\napi_key = pk1_a\nsecret_key = sk1_a\n", 0o600))
+	_, diags = resolveCredentials(context.Background(), data)
+	if !diags.HasError() || diags[0].Summary() != "Unable to find profile" {
+		t.Errorf("expected the missing profile to be an error, got %v", diags)
+	}
+}
+
+func Test_ResolveCredentialsInsecureFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not checked on windows")
+	}
+
+	t.Setenv("PORKBUN_API_KEY", "")
+	t.Setenv("PORKBUN_SECRET_KEY", "")
+
+	data := emptyProviderModel()
+	data.CredentialsFile = types.StringValue(writeCredentialsFile(t, "[default]\napi_key = pk1_a\nsecret_key = sk1_a\n", 0o644))
+
+	_, diags := resolveCredentials(context.Background(), data)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the file permissions, got %v", diags)
+	}
+}
+
+func Test_SplitCommand(t *testing.T) {
+	tests := map[string][]string{
+		`vault kv get -field=porkbun secret/dns`: {"vault", "kv", "get", "-field=porkbun", "secret/dns"},
+		`sh -c 'echo "$KEYS"'`:                   {"sh", "-c", `echo "$KEYS"`},
+		`op read "op://Shared Vault/porkbun"`:    {"op", "read", "op://Shared Vault/porkbun"},
+		`cat /tmp/with\ space "a\"b" ''`:         {"cat", "/tmp/with space", `a"b`, ""},
+	}
+
+	for command, expected := range tests {
+		actual, err := splitCommand(command)
+		if err != nil {
+			t.Errorf("%s: %s", command, err)
+			continue
+		}
+
+		if !slices.Equal(actual, expected) {
+			t.Errorf("%s: expected %q, got %q", command, expected, actual)
+		}
+	}
+
+	if _, err := splitCommand(`echo "unterminated`); err == nil {
+		t.Error("expected an unterminated quote to be an error")
+	}
+}
+
+func Test_RunCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	command := `sh -c 'echo run >> ` + counter + `; echo "{\"api_key\": \"pk1_process\", \"secret_key\": \"sk1_process\"}"'`
+
+	for i := 0; i < 2; i++ {
+		output, err := runCredentialProcess(context.Background(), command)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output.ApiKey != "pk1_process" || output.SecretKey != "sk1_process" {
+			t.Errorf("unexpected output %+v", output)
+		}
+	}
+
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(runs), "run") != 1 {
+		t.Errorf("expected the command to run once, ran %d times", strings.Count(string(runs), "run"))
+	}
+
+	_, err = runCredentialProcess(context.Background(), `sh -c 'echo sk1_leaked'`)
+	if err == nil || strings.Contains(err.Error(), "sk1_leaked") {
+		t.Errorf("expected an error without the output of the command, got %v", err)
+	}
+
+	_, err = runCredentialProcess(context.Background(), `sh -c 'echo sk1_leaked >&2; exit 1'`)
+	if err == nil || strings.Contains(err.Error(), "sk1_leaked") {
+		t.Errorf("expected an error without the stderr of the command, got %v", err)
+	}
+}
+
+func Test_RunCredentialProcessConcurrently(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+
+	// The first command waits for the second, which must not wait for the first to finish
+	started, marker := filepath.Join(t.TempDir(), "started"), filepath.Join(t.TempDir(), "marker")
+	keys := `echo "{\"api_key\": \"pk1_process\", \"secret_key\": \"sk1_process\"}"`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	waiting := make(chan error)
+	go func() {
+		_, err := runCredentialProcess(ctx, `sh -c 'touch `+started+`; while [ ! -f `+marker+` ]; do sleep 0.01; done; `+keys+`'`)
+		waiting <- err
+	}()
+
+	for {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatal("the first command never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := runCredentialProcess(ctx, `sh -c 'touch `+marker+`; `+keys+`'`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-waiting; err != nil {
+		t.Errorf("expected the waiting command to finish once the other one ran, got %s", err)
+	}
+}
+
+func Test_ResolveCredentialsFromProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+
+	t.Setenv("PORKBUN_API_KEY", "")
+	t.Setenv("PORKBUN_SECRET_KEY", "")
+
+	data := emptyProviderModel()
+	data.CredentialsFile = types.StringValue(writeCredentialsFile(t, "[default]\ncredential_process = echo '{\"api_key\": \"pk1_profile_process\", \"secret_key\": \"sk1_profile_process\"}'\nsecret_key = sk1_file\n", 0o600))
+
+	creds, diags := resolveCredentials(context.Background(), data)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if creds.apiKey != "pk1_profile_process" || creds.secretKey != "sk1_profile_process" || !strings.HasPrefix(creds.source(), "the credential_process of profile") {
+		t.Errorf("expected the keys from the credential_process of the profile, got %q and %q from %s", creds.apiKey, creds.secretKey, creds.source())
+	}
+}
+
+func Test_ValidateKeyFormat(t *testing.T) {
+	creds := credentials{
+		apiKey:          "sk1_swapped",
+		secretKey:       "sk1_secret",
+		apiKeySource:    "the provider configuration",
+		secretKeySource: "the provider configuration",
+	}
+
+	diags := validateKeyFormat(creds)
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "Invalid api_key" {
+		t.Errorf("expected only the api key to be invalid, got %v", diags)
+	}
+
+	creds.apiKey = "pk1_key"
+	if diags := validateKeyFormat(creds); diags.HasError() {
+		t.Errorf("expected the keys to be valid, got %v", diags)
+	}
+}
+
+func Test_PingCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		expected string
+	}{
+		{"valid", http.StatusOK, `{"status": "SUCCESS", "yourIp": "127.0.0.1"}`, ""},
+		{"invalid", http.StatusOK, `{"status": "ERROR", "message": "Invalid API key. (002)"}`, "Invalid Porkbun credentials"},
+		{"invalid with bad request", http.StatusBadRequest, `{"status": "ERROR", "message": "Invalid API key. (002)"}`, "Invalid Porkbun credentials"},
+		{"other error", http.StatusOK, `{"status": "ERROR", "message": "Something went wrong"}`, "Unable to validate Porkbun credentials"},
+	}
+
+	for _, tc := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(tc.response))
+		}))
+
+		client := porkbun.New("sk1_secret", "pk1_key")
+		client.BaseURL, _ = url.Parse(server.URL)
+
+		diags := pingCredentials(context.Background(), client, credentials{apiKeySource: "the provider configuration", secretKeySource: "the provider configuration"})
+		server.Close()
+
+		switch {
+		case tc.expected == "" && diags.HasError():
+			t.Errorf("%s: expected no error, got %v", tc.name, diags)
+		case tc.expected != "" && (!diags.HasError() || diags[0].Summary() != tc.expected):
+			t.Errorf("%s: expected %q, got %v", tc.name, tc.expected, diags)
+		}
+	}
+}
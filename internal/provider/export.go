@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/nrdcg/porkbun"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// exportLabelInvalid matches the characters that can't be part of a resource label
+var exportLabelInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// Export writes the records of the domain to w as porkbun_dns_record resources together with the import blocks that
+// adopt them. The keys are taken from the environment, the credential process or the credentials file like the
+// provider does without configuration.
+func Export(ctx context.Context, w io.Writer, domain string) error {
+	client, err := newExportClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return exportRecords(ctx, w, client, domain)
+}
+
+// newExportClient creates a client with the credentials and base URL the provider uses when it isn't configured
+func newExportClient(ctx context.Context) (*porkbun.Client, error) {
+	creds, diags := resolveCredentials(ctx, PorkbunProviderModel{})
+	diags.Append(validateKeyFormat(creds)...)
+	if diags.HasError() {
+		d := diags.Errors()[0]
+		return nil, fmt.Errorf("%s: %s", d.Summary(), d.Detail())
+	}
+
+	client := porkbun.New(creds.secretKey, creds.apiKey)
+	if baseUrl, ok := os.LookupEnv("PORKBUN_BASE_URL"); ok && baseUrl != "" {
+		parsed, err := url.Parse(baseUrl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PORKBUN_BASE_URL: %w", err)
+		}
+		client.BaseURL = parsed
+	}
+
+	return client, nil
+}
+
+// exportRecords writes the records of the domain as configuration. Attributes that have their default value are left
+// out, so the configuration matches what would have been written by hand.
+func exportRecords(ctx context.Context, w io.Writer, client *porkbun.Client, domain string) error {
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	ascii, err := normalizeDomainName(domain)
+	if err != nil {
+		return err
+	}
+	if ascii == "" {
+		return fmt.Errorf("the domain must not be empty")
+	}
+
+	records, err := client.RetrieveRecords(ctx, ascii)
+	if err != nil {
+		return fmt.Errorf("could not retrieve the records of %s: %w", domain, err)
+	}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	labels := map[string]bool{}
+
+	for i, record := range records {
+		name, err := relativeRecordName(record.Name, ascii)
+		if err != nil {
+			return fmt.Errorf("record %s: %w", record.ID, err)
+		}
+
+		label := exportLabel(name, record.Type, labels)
+
+		if i > 0 {
+			body.AppendNewline()
+		}
+
+		resource := body.AppendNewBlock("resource", []string{"porkbun_dns_record", label}).Body()
+		resource.SetAttributeValue("domain", cty.StringVal(domain))
+		if name != "" {
+			resource.SetAttributeValue("name", cty.StringVal(name))
+		}
+		resource.SetAttributeValue("type", cty.StringVal(record.Type))
+
+		content := record.Content
+		if strings.EqualFold(record.Type, "TXT") {
+			content = parseTxtContent(content)
+		}
+		resource.SetAttributeValue("content", cty.StringVal(content))
+
+		if record.TTL != "" && record.TTL != "600" {
+			resource.SetAttributeValue("ttl", cty.StringVal(record.TTL))
+		}
+		if record.Prio != "" && record.Prio != "0" {
+			resource.SetAttributeValue("prio", cty.StringVal(record.Prio))
+		}
+		if record.Notes != "" {
+			resource.SetAttributeValue("notes", cty.StringVal(record.Notes))
+		}
+
+		body.AppendNewline()
+
+		imp := body.AppendNewBlock("import", nil).Body()
+		imp.SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: "porkbun_dns_record"},
+			hcl.TraverseAttr{Name: label},
+		})
+		imp.SetAttributeValue("id", cty.StringVal(domain+"/"+record.ID))
+	}
+
+	_, err = w.Write(hclwrite.Format(file.Bytes()))
+	return err
+}
+
+// exportLabel returns a unique resource label for a record from its name and type, like www_a or apex_mx. Labels
+// that are already taken get a number, like www_a_2.
+func exportLabel(name string, recordType string, labels map[string]bool) string {
+	if name == "" {
+		name = "apex"
+	}
+	name = strings.ReplaceAll(name, "*", "wildcard")
+
+	label := exportLabelInvalid.ReplaceAllString(strings.ToLower(name+"_"+recordType), "_")
+	// Labels must start with a letter or underscore
+	if label[0] >= '0' && label[0] <= '9' || label[0] == '-' {
+		label = "_" + label
+	}
+
+	unique := label
+	for i := 2; labels[unique]; i++ {
+		unique = label + "_" + strconv.Itoa(i)
+	}
+	labels[unique] = true
+
+	return unique
+}
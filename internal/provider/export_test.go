@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newExportServer starts a fake API with records of every kind the export has to handle
+func newExportServer(t *testing.T) *porkbuntest.Server {
+	server := newTestServer(t)
+	// Keep the credentials file of the user out of the test
+	t.Setenv("HOME", t.TempDir())
+
+	for _, record := range []porkbuntest.Record{
+		{Name: "", Type: "A", Content: "192.0.2.1"},
+		{Name: "", Type: "MX", Content: "mail.providertest.top", Prio: "10", TTL: "3600"},
+		{Name: "www", Type: "A", Content: "192.0.2.2", Notes: "web ${server}"},
+		{Name: "www", Type: "A", Content: "192.0.2.3"},
+		{Name: "*.dev", Type: "CNAME", Content: "providertest.top"},
+		{Name: "mail._domainkey", Type: "TXT", Content: `"v=DKIM1; k=rsa; " "p=MIIB"`},
+	} {
+		server.AddRecord(testDomain, record)
+	}
+
+	return server
+}
+
+func Test_Export(t *testing.T) {
+	newExportServer(t)
+
+	var out bytes.Buffer
+	if err := Export(context.Background(), &out, testDomain+"."); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `resource "porkbun_dns_record" "wildcard_dev_cname" {
+  domain  = "providertest.top"
+  name    = "*.dev"
+  type    = "CNAME"
+  content = "providertest.top"
+}
+
+import {
+  to = porkbun_dns_record.wildcard_dev_cname
+  id = "providertest.top/100000005"
+}
+
+resource "porkbun_dns_record" "mail__domainkey_txt" {
+  domain  = "providertest.top"
+  name    = "mail._domainkey"
+  type    = "TXT"
+  content = "v=DKIM1; k=rsa; p=MIIB"
+}
+
+import {
+  to = porkbun_dns_record.mail__domainkey_txt
+  id = "providertest.top/100000006"
+}
+
+resource "porkbun_dns_record" "apex_a" {
+  domain  = "providertest.top"
+  type    = "A"
+  content = "192.0.2.1"
+}
+
+import {
+  to = porkbun_dns_record.apex_a
+  id = "providertest.top/100000001"
+}
+
+resource "porkbun_dns_record" "apex_mx" {
+  domain  = "providertest.top"
+  type    = "MX"
+  content = "mail.providertest.top"
+  ttl     = "3600"
+  prio    = "10"
+}
+
+import {
+  to = porkbun_dns_record.apex_mx
+  id = "providertest.top/100000002"
+}
+
+resource "porkbun_dns_record" "www_a" {
+  domain  = "providertest.top"
+  name    = "www"
+  type    = "A"
+  content = "192.0.2.2"
+  notes   = "web $${server}"
+}
+
+import {
+  to = porkbun_dns_record.www_a
+  id = "providertest.top/100000003"
+}
+
+resource "porkbun_dns_record" "www_a_2" {
+  domain  = "providertest.top"
+  name    = "www"
+  type    = "A"
+  content = "192.0.2.3"
+}
+
+import {
+  to = porkbun_dns_record.www_a_2
+  id = "providertest.top/100000004"
+}
+`
+
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func Test_ExportErrors(t *testing.T) {
+	newExportServer(t)
+
+	if err := Export(context.Background(), &bytes.Buffer{}, "unknown.top"); err == nil {
+		t.Error("expected a domain without a zone to be an error")
+	}
+
+	t.Setenv("PORKBUN_API_KEY", "")
+	if err := Export(context.Background(), &bytes.Buffer{}, testDomain); err == nil || !strings.Contains(err.Error(), "api_key") {
+		t.Errorf("expected missing keys to be an error, got %v", err)
+	}
+}
+
+func Test_ExportLabel(t *testing.T) {
+	labels := map[string]bool{}
+
+	tests := []struct {
+		name       string
+		recordType string
+		expected   string
+	}{
+		{"", "A", "apex_a"},
+		{"www", "AAAA", "www_aaaa"},
+		{"_25._tcp.mail", "TLSA", "_25__tcp_mail_tlsa"},
+		{"25-foo", "A", "_25-foo_a"},
+		{"xn--bcher-kva", "A", "xn--bcher-kva_a"},
+		{"www", "AAAA", "www_aaaa_2"},
+		{"www", "AAAA", "www_aaaa_3"},
+	}
+
+	for _, tc := range tests {
+		if actual := exportLabel(tc.name, tc.recordType, labels); actual != tc.expected {
+			t.Errorf("%q, %q: expected %q, got %q", tc.name, tc.recordType, tc.expected, actual)
+		}
+	}
+}
+
+// The exported configuration imports every record without planning any changes
+func Test_ExportedConfigImports(t *testing.T) {
+	server := newExportServer(t)
+
+	var out bytes.Buffer
+	if err := Export(context.Background(), &out, testDomain); err != nil {
+		t.Fatal(err)
+	}
+
+	records := server.Records(testDomain)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config: out.String(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.mail__domainkey_txt", "content", "v=DKIM1; k=rsa; p=MIIB"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.apex_mx", "prio", "10"),
+					func(s *terraform.State) error {
+						if actual := server.Records(testDomain); len(actual) != len(records) {
+							return fmt.Errorf("expected the %d records to be imported, found %d", len(records), len(actual))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The imported records match the exported configuration
+				Config:             out.String(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				ResourceName:  "porkbun_dns_record.apex_a",
+				ImportState:   true,
+				ImportStateId: records[2].ID,
+				ExpectError:   regexp.MustCompile(`Expected an ID of the form <domain>/<id>`),
+			},
+			{
+				ResourceName: "porkbun_dns_record.apex_a",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return testDomain + "/" + s.RootModule().Resources["porkbun_dns_record.apex_a"].Primary.ID, nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
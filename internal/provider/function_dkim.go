@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = dkimFunction{}
+
+func NewDkimFunction() function.Function {
+	return dkimFunction{}
+}
+
+type dkimFunction struct{}
+
+func (f dkimFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dkim"
+}
+
+func (f dkimFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Content of a DKIM record",
+		MarkdownDescription: "Returns the content of the `<selector>._domainkey` `TXT` record publishing a DKIM public key, e.g. `v=DKIM1; k=rsa; p=MIIB...`. RSA keys need at least 1024 bits, Ed25519 keys are supported as well. Values longer than 255 characters are split into quoted strings by `porkbun_dns_record`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "public_key_pem",
+				MarkdownDescription: "The PEM encoded public key, e.g. `public_key_pem` of a `tls_private_key`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f dkimFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var publicKeyPem string
+
+	resp.Error = req.Arguments.Get(ctx, &publicKeyPem)
+	if resp.Error != nil {
+		return
+	}
+
+	content, err := buildDkimRecord(publicKeyPem)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, content)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_DkimFunction(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der)
+
+	for _, block := range []*pem.Block{
+		{Type: "PUBLIC KEY", Bytes: der},
+		{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)},
+	} {
+		result, err := runFunction(t, NewDkimFunction(), types.StringValue(string(pem.EncodeToMemory(block))))
+		if err != nil || !result.Equal(types.StringValue(expected)) {
+			t.Errorf("%s: expected %s, got %v: %v", block.Type, expected, result, err)
+		}
+	}
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err = x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, funcErr := runFunction(t, NewDkimFunction(), types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))))
+	if funcErr != nil || !result.Equal(types.StringValue("v=DKIM1; k=ed25519; p="+base64.StdEncoding.EncodeToString(public))) {
+		t.Errorf("unexpected Ed25519 record %v: %v", result, funcErr)
+	}
+
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if _, err := runFunction(t, NewDkimFunction(), types.StringValue(string(private))); err == nil || !strings.Contains(err.Error(), "public key") {
+		t.Errorf("expected a private key to be an error, got %v", err)
+	}
+
+	if _, err := runFunction(t, NewDkimFunction(), types.StringValue("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA")); err == nil {
+		t.Error("expected a key without PEM encoding to be an error")
+	}
+}
+
+func Test_DkimFunctionSmallKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runFunction(t, NewDkimFunction(), types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))); err == nil {
+		t.Error("expected a 512 bit key to be an error")
+	}
+}
@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	dmarcPolicies       = []string{"none", "quarantine", "reject"}
+	dmarcAlignments     = map[string]string{"relaxed": "r", "strict": "s"}
+	dmarcFailureOptions = []string{"0", "1", "d", "s"}
+)
+
+// dmarcOptions are the typed arguments of the dmarc function
+type dmarcOptions struct {
+	policy          string
+	subdomainPolicy string
+	percentage      *int64
+	rua             []string
+	ruf             []string
+	adkim           string
+	aspf            string
+	failureOptions  []string
+	reportInterval  *int64
+}
+
+var _ function.Function = dmarcFunction{}
+
+func NewDmarcFunction() function.Function {
+	return dmarcFunction{}
+}
+
+type dmarcFunction struct{}
+
+func (f dmarcFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dmarc"
+}
+
+func (f dmarcFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Content of a DMARC record",
+		MarkdownDescription: "Returns the content of the `_dmarc` `TXT` record for a DMARC policy. The options are an object with the attributes\n\n" +
+			"- `policy` (required) - `none`, `quarantine` or `reject`\n" +
+			"- `subdomain_policy` - the policy for subdomains, `none`, `quarantine` or `reject`\n" +
+			"- `percentage` - the percentage of messages the policy applies to, from 0 to 100\n" +
+			"- `rua` - `mailto:` URIs to send aggregate reports to\n" +
+			"- `ruf` - `mailto:` URIs to send failure reports to\n" +
+			"- `adkim` - the DKIM alignment, `relaxed` or `strict`\n" +
+			"- `aspf` - the SPF alignment, `relaxed` or `strict`\n" +
+			"- `failure_options` - when to send failure reports, a list of `0`, `1`, `d` and `s`\n" +
+			"- `report_interval` - the interval of aggregate reports in seconds",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "options",
+				MarkdownDescription: "The DMARC policy, e.g. `{ policy = \"reject\", rua = [\"mailto:dmarc@example.com\"] }`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f dmarcFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var options types.Dynamic
+
+	resp.Error = req.Arguments.Get(ctx, &options)
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := parseDmarcOptions(options.UnderlyingValue())
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	content, err := parsed.content()
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, content)
+}
+
+// parseDmarcOptions reads the options object of the dmarc function
+func parseDmarcOptions(value attr.Value) (dmarcOptions, error) {
+	var options dmarcOptions
+
+	var attributes map[string]attr.Value
+	switch v := value.(type) {
+	case types.Object:
+		attributes = v.Attributes()
+	case types.Map:
+		attributes = v.Elements()
+	default:
+		return options, fmt.Errorf("the options must be an object like { policy = \"reject\" }")
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := attributes[name]
+		if value.IsNull() {
+			continue
+		}
+		if value.IsUnknown() {
+			return options, fmt.Errorf("%s must be known", name)
+		}
+
+		var err error
+		switch name {
+		case "policy":
+			options.policy, err = dynamicString(name, value)
+		case "subdomain_policy":
+			options.subdomainPolicy, err = dynamicString(name, value)
+		case "percentage":
+			options.percentage, err = dynamicInt(name, value)
+		case "rua":
+			options.rua, err = dynamicStrings(name, value)
+		case "ruf":
+			options.ruf, err = dynamicStrings(name, value)
+		case "adkim":
+			options.adkim, err = dynamicString(name, value)
+		case "aspf":
+			options.aspf, err = dynamicString(name, value)
+		case "failure_options":
+			options.failureOptions, err = dynamicStrings(name, value)
+		case "report_interval":
+			options.reportInterval, err = dynamicInt(name, value)
+		default:
+			err = fmt.Errorf("unknown option %q, expected policy, subdomain_policy, percentage, rua, ruf, adkim, aspf, failure_options or report_interval", name)
+		}
+		if err != nil {
+			return options, err
+		}
+	}
+
+	return options, nil
+}
+
+// content renders the options as a DMARC record, with the tags in the order of RFC 7489
+func (o dmarcOptions) content() (string, error) {
+	if o.policy == "" {
+		return "", fmt.Errorf("policy is required")
+	}
+	if !slices.Contains(dmarcPolicies, o.policy) {
+		return "", fmt.Errorf("policy must be one of %s, got %q", strings.Join(dmarcPolicies, ", "), o.policy)
+	}
+
+	tags := []string{"v=DMARC1", "p=" + o.policy}
+
+	if o.subdomainPolicy != "" {
+		if !slices.Contains(dmarcPolicies, o.subdomainPolicy) {
+			return "", fmt.Errorf("subdomain_policy must be one of %s, got %q", strings.Join(dmarcPolicies, ", "), o.subdomainPolicy)
+		}
+		tags = append(tags, "sp="+o.subdomainPolicy)
+	}
+
+	if o.percentage != nil {
+		if *o.percentage < 0 || *o.percentage > 100 {
+			return "", fmt.Errorf("percentage must be between 0 and 100, got %d", *o.percentage)
+		}
+		tags = append(tags, fmt.Sprintf("pct=%d", *o.percentage))
+	}
+
+	for _, uris := range []struct {
+		tag  string
+		uris []string
+	}{{"rua", o.rua}, {"ruf", o.ruf}} {
+		if uris.uris == nil {
+			continue
+		}
+		if err := validateReportUris(uris.uris, "mailto:"); err != nil {
+			return "", fmt.Errorf("%s: %w", uris.tag, err)
+		}
+		tags = append(tags, uris.tag+"="+strings.Join(uris.uris, ","))
+	}
+
+	for _, alignment := range []struct {
+		tag   string
+		value string
+	}{{"adkim", o.adkim}, {"aspf", o.aspf}} {
+		if alignment.value == "" {
+			continue
+		}
+		short, ok := dmarcAlignments[alignment.value]
+		if !ok {
+			return "", fmt.Errorf("%s must be relaxed or strict, got %q", alignment.tag, alignment.value)
+		}
+		tags = append(tags, alignment.tag+"="+short)
+	}
+
+	if o.failureOptions != nil {
+		for _, option := range o.failureOptions {
+			if !slices.Contains(dmarcFailureOptions, option) {
+				return "", fmt.Errorf("failure_options must only contain %s, got %q", strings.Join(dmarcFailureOptions, ", "), option)
+			}
+		}
+		tags = append(tags, "fo="+strings.Join(o.failureOptions, ":"))
+	}
+
+	if o.reportInterval != nil {
+		if *o.reportInterval < 1 {
+			return "", fmt.Errorf("report_interval must be positive, got %d", *o.reportInterval)
+		}
+		tags = append(tags, fmt.Sprintf("ri=%d", *o.reportInterval))
+	}
+
+	return strings.Join(tags, "; "), nil
+}
+
+func dynamicString(name string, value attr.Value) (string, error) {
+	switch v := value.(type) {
+	case types.String:
+		return v.ValueString(), nil
+	case types.Number:
+		return v.ValueBigFloat().Text('f', -1), nil
+	}
+
+	return "", fmt.Errorf("%s must be a string", name)
+}
+
+func dynamicInt(name string, value attr.Value) (*int64, error) {
+	switch v := value.(type) {
+	case types.Number:
+		number, accuracy := v.ValueBigFloat().Int64()
+		if accuracy != 0 {
+			return nil, fmt.Errorf("%s must be a whole number", name)
+		}
+		return &number, nil
+	case types.String:
+		number, err := strconv.ParseInt(v.ValueString(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number", name)
+		}
+		return &number, nil
+	}
+
+	return nil, fmt.Errorf("%s must be a number", name)
+}
+
+func dynamicStrings(name string, value attr.Value) ([]string, error) {
+	var elements []attr.Value
+	switch v := value.(type) {
+	case types.Tuple:
+		elements = v.Elements()
+	case types.List:
+		elements = v.Elements()
+	case types.Set:
+		elements = v.Elements()
+	case types.String:
+		// A single value is accepted for convenience
+		return []string{v.ValueString()}, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings", name)
+	}
+
+	values := make([]string, 0, len(elements))
+	for _, element := range elements {
+		s, err := dynamicString(name, element)
+		if err != nil || element.IsNull() || element.IsUnknown() {
+			return nil, fmt.Errorf("%s must be a list of strings", name)
+		}
+		values = append(values, s)
+	}
+
+	return values, nil
+}
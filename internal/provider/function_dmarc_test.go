@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func dmarcOptionsValue(attributes map[string]attr.Value) types.Dynamic {
+	attributeTypes := map[string]attr.Type{}
+	for name, value := range attributes {
+		attributeTypes[name] = value.Type(nil)
+	}
+
+	return types.DynamicValue(types.ObjectValueMust(attributeTypes, attributes))
+}
+
+func Test_DmarcFunction(t *testing.T) {
+	rua := types.TupleValueMust(
+		[]attr.Type{types.StringType, types.StringType},
+		[]attr.Value{types.StringValue("mailto:dmarc@example.com"), types.StringValue("mailto:reports@example.net")},
+	)
+
+	options := dmarcOptionsValue(map[string]attr.Value{
+		"policy":           types.StringValue("reject"),
+		"subdomain_policy": types.StringValue("quarantine"),
+		"percentage":       types.NumberValue(big.NewFloat(50)),
+		"rua":              rua,
+		"adkim":            types.StringValue("strict"),
+		"aspf":             types.StringValue("relaxed"),
+		"failure_options":  types.TupleValueMust([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("1"), types.StringValue("d")}),
+		"report_interval":  types.NumberValue(big.NewFloat(86400)),
+	})
+
+	expected := "v=DMARC1; p=reject; sp=quarantine; pct=50; rua=mailto:dmarc@example.com,mailto:reports@example.net; adkim=s; aspf=r; fo=1:d; ri=86400"
+	result, err := runFunction(t, NewDmarcFunction(), options)
+	if err != nil || !result.Equal(types.StringValue(expected)) {
+		t.Errorf("expected %q, got %v: %v", expected, result, err)
+	}
+
+	result, err = runFunction(t, NewDmarcFunction(), dmarcOptionsValue(map[string]attr.Value{"policy": types.StringValue("none")}))
+	if err != nil || !result.Equal(types.StringValue("v=DMARC1; p=none")) {
+		t.Errorf("expected the minimal policy, got %v: %v", result, err)
+	}
+
+	invalid := []map[string]attr.Value{
+		{"subdomain_policy": types.StringValue("reject")},
+		{"policy": types.StringValue("block")},
+		{"policy": types.StringValue("reject"), "percentage": types.NumberValue(big.NewFloat(101))},
+		{"policy": types.StringValue("reject"), "percentage": types.NumberValue(big.NewFloat(12.5))},
+		{"policy": types.StringValue("reject"), "rua": types.StringValue("https://example.com/dmarc")},
+		{"policy": types.StringValue("reject"), "adkim": types.StringValue("s")},
+		{"policy": types.StringValue("reject"), "pct": types.NumberValue(big.NewFloat(100))},
+	}
+
+	for _, attributes := range invalid {
+		if result, err := runFunction(t, NewDmarcFunction(), dmarcOptionsValue(attributes)); err == nil {
+			t.Errorf("%v: expected an error, got %v", attributes, result)
+		}
+	}
+
+	if _, err := runFunction(t, NewDmarcFunction(), types.DynamicValue(types.StringValue("reject"))); err == nil {
+		t.Error("expected options that aren't an object to be an error")
+	}
+}
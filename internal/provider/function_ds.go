@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dsAttributeTypes are the attributes of the object the ds function returns
+var dsAttributeTypes = map[string]attr.Type{
+	"key_tag":   types.Int64Type,
+	"algorithm": types.Int64Type,
+	"sha256":    types.StringType,
+	"sha384":    types.StringType,
+}
+
+var _ function.Function = dsFunction{}
+
+func NewDsFunction() function.Function {
+	return dsFunction{}
+}
+
+type dsFunction struct{}
+
+func (f dsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ds"
+}
+
+func (f dsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "DS digests of a DNSKEY",
+		MarkdownDescription: "Returns the data of the DS records for a DNSKEY record of the domain, to be submitted to the registry. The object has the attributes\n\n" +
+			"- `key_tag` - the key tag of the DNSKEY\n" +
+			"- `algorithm` - the algorithm of the DNSKEY\n" +
+			"- `sha256` - the SHA-256 digest, digest type 2, in hex\n" +
+			"- `sha384` - the SHA-384 digest, digest type 4, in hex",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "domain",
+				MarkdownDescription: "The domain the DNSKEY record belongs to",
+			},
+			function.StringParameter{
+				Name:                "dnskey",
+				MarkdownDescription: "The DNSKEY record in presentation format, either its data like `257 3 13 mdsswUyr...` or the whole record like `example.com. 3600 IN DNSKEY 257 3 13 mdsswUyr...`",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: dsAttributeTypes,
+		},
+	}
+}
+
+func (f dsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var domain, value string
+
+	resp.Error = req.Arguments.Get(ctx, &domain, &value)
+	if resp.Error != nil {
+		return
+	}
+
+	key, err := parseDnskey(value)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	digests := map[string]attr.Value{}
+	for attribute, digestType := range map[string]uint8{"sha256": dsDigestSha256, "sha384": dsDigestSha384} {
+		digest, err := key.dsDigest(domain, digestType)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(0, err.Error())
+			return
+		}
+		digests[attribute] = types.StringValue(digest)
+	}
+
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(dsAttributeTypes, map[string]attr.Value{
+		"key_tag":   types.Int64Value(int64(key.keyTag())),
+		"algorithm": types.Int64Value(int64(key.algorithm)),
+		"sha256":    digests["sha256"],
+		"sha384":    digests["sha384"],
+	}))
+}
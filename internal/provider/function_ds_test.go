@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_DsFunction(t *testing.T) {
+	dnskey := types.StringValue("example.net. 3600 IN DNSKEY 257 3 13 GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==")
+
+	result, err := runFunction(t, NewDsFunction(), types.StringValue("example.net"), dnskey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributes := result.(types.Object).Attributes()
+	if !attributes["key_tag"].Equal(types.Int64Value(55648)) || !attributes["algorithm"].Equal(types.Int64Value(13)) ||
+		!attributes["sha256"].Equal(types.StringValue("B4C8C1FE2E7477127B27115656AD6256F424625BF5C1E2770CE6D6E37DF61D17")) {
+		t.Errorf("unexpected result %v", result)
+	}
+	if sha384 := attributes["sha384"].(types.String).ValueString(); len(sha384) != 96 {
+		t.Errorf("expected a SHA-384 digest, got %q", sha384)
+	}
+
+	if _, err := runFunction(t, NewDsFunction(), types.StringValue("example.net"), types.StringValue("257 3 13")); err == nil {
+		t.Error("expected an incomplete DNSKEY to be an error")
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = fqdnFunction{}
+
+func NewFqdnFunction() function.Function {
+	return fqdnFunction{}
+}
+
+type fqdnFunction struct{}
+
+func (f fqdnFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "fqdn"
+}
+
+func (f fqdnFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Full name of a record",
+		MarkdownDescription: "Returns the fully qualified name of a record from its `name` and `domain` the way Porkbun returns it, in lowercase without a trailing dot and with internationalized labels in punycode. An empty name or `@` is the apex, and a name with a trailing dot is taken as already fully qualified.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The name of the record relative to the domain, e.g. `www`, `*.dev` or an empty string for the apex",
+			},
+			function.StringParameter{
+				Name:                "domain",
+				MarkdownDescription: "The base domain of the record",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f fqdnFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name, domain string
+
+	resp.Error = req.Arguments.Get(ctx, &name, &domain)
+	if resp.Error != nil {
+		return
+	}
+
+	fqdn, err := recordFqdn(name, domain)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, fqdn)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_FqdnFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		expected string
+	}{
+		{"www", "Example.com.", "www.example.com"},
+		{"", "example.com", "example.com"},
+		{"@", "example.com", "example.com"},
+		{"*.dev", "example.com", "*.dev.example.com"},
+		{"mail.example.com.", "example.com.", "mail.example.com"},
+		{"WWW.example.com.", "example.com", "www.example.com"},
+		{"example.com.", "example.com", "example.com"},
+		{"bücher", "münchen.de", "xn--bcher-kva.xn--mnchen-3ya.de"},
+	}
+
+	for _, tc := range tests {
+		result, err := runFunction(t, NewFqdnFunction(), types.StringValue(tc.name), types.StringValue(tc.domain))
+		if err != nil || !result.Equal(types.StringValue(tc.expected)) {
+			t.Errorf("%q, %q: expected %s, got %v: %v", tc.name, tc.domain, tc.expected, result, err)
+		}
+	}
+
+	_, err := runFunction(t, NewFqdnFunction(), types.StringValue("www.example.org."), types.StringValue("example.com"))
+	if expected := function.NewFuncError(`"www.example.org." is not within "example.com"`); !err.Equal(expected) {
+		t.Errorf("expected %v for a name outside of the domain, got %v", expected, err)
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// mtaStsId matches the policy ID of an MTA-STS record, RFC 8461 section 3.1
+var mtaStsId = regexp.MustCompile(`^[A-Za-z0-9]{1,32}$`)
+
+var _ function.Function = mtaStsFunction{}
+
+func NewMtaStsFunction() function.Function {
+	return mtaStsFunction{}
+}
+
+type mtaStsFunction struct{}
+
+func (f mtaStsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "mta_sts"
+}
+
+func (f mtaStsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Content of an MTA-STS record",
+		MarkdownDescription: "Returns the content of the `_mta-sts` `TXT` record announcing an MTA-STS policy.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "The ID of the current policy, up to 32 letters and digits. Change it whenever the policy changes, e.g. to a timestamp like `20240101000000`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f mtaStsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+
+	resp.Error = req.Arguments.Get(ctx, &id)
+	if resp.Error != nil {
+		return
+	}
+
+	if !mtaStsId.MatchString(id) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("the policy ID must be 1 to 32 letters and digits, got %q", id))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, "v=STSv1; id="+id)
+}
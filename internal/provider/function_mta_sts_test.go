@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_MtaStsFunction(t *testing.T) {
+	result, err := runFunction(t, NewMtaStsFunction(), types.StringValue("20240101000000"))
+	if err != nil || !result.Equal(types.StringValue("v=STSv1; id=20240101000000")) {
+		t.Errorf("unexpected result %v: %v", result, err)
+	}
+
+	for _, id := range []string{"", "2024-01-01", "123456789012345678901234567890123"} {
+		if _, err := runFunction(t, NewMtaStsFunction(), types.StringValue(id)); err == nil {
+			t.Errorf("%q: expected an invalid policy ID to be an error", id)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// zoneRecordAttributeTypes are the attributes of the records parse_zone returns, named like those of
+// porkbun_dns_record
+var zoneRecordAttributeTypes = map[string]attr.Type{
+	"name":    types.StringType,
+	"type":    types.StringType,
+	"content": types.StringType,
+	"ttl":     types.StringType,
+	"prio":    types.StringType,
+}
+
+var _ function.Function = parseZoneFunction{}
+
+func NewParseZoneFunction() function.Function {
+	return parseZoneFunction{}
+}
+
+type parseZoneFunction struct{}
+
+func (f parseZoneFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_zone"
+}
+
+func (f parseZoneFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Records of a zone file",
+		MarkdownDescription: "Parses a zone file in the format of RFC 1035 and returns its records as objects with the `name`, `type`, `content`, `ttl` and `prio` attributes of `porkbun_dns_record`. " +
+			"Names are relative to the origin, `$ORIGIN` and `$TTL` are supported, host names in the content of `CNAME`, `MX`, `NS` and `SRV` records are fully qualified and the strings of a `TXT` record are joined into a single value. " +
+			"The `SOA` record is skipped because Porkbun manages it, and TTLs below Porkbun's minimum of 600 are raised to it.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "text",
+				MarkdownDescription: "The content of the zone file, e.g. from `file(\"example.com.zone\")`",
+			},
+			function.StringParameter{
+				Name:                "origin",
+				MarkdownDescription: "The domain of the zone, which relative names are qualified with until a `$ORIGIN` directive",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.ObjectType{AttrTypes: zoneRecordAttributeTypes},
+		},
+	}
+}
+
+func (f parseZoneFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text, origin string
+
+	resp.Error = req.Arguments.Get(ctx, &text, &origin)
+	if resp.Error != nil {
+		return
+	}
+
+	records, err := parseZoneFile(text, origin)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	elements := make([]attr.Value, 0, len(records))
+	for _, record := range records {
+		elements = append(elements, types.ObjectValueMust(zoneRecordAttributeTypes, map[string]attr.Value{
+			"name":    types.StringValue(record.name),
+			"type":    types.StringValue(record.typ),
+			"content": types.StringValue(record.content),
+			"ttl":     types.StringValue(record.ttl),
+			"prio":    types.StringValue(record.prio),
+		}))
+	}
+
+	resp.Error = resp.Result.Set(ctx, types.ListValueMust(types.ObjectType{AttrTypes: zoneRecordAttributeTypes}, elements))
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_ParseZoneFunction(t *testing.T) {
+	result, err := runFunction(t, NewParseZoneFunction(), types.StringValue("www 3600 IN CNAME @\n@ MX 10 mail\n"), types.StringValue("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := result.(types.List).Elements()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", result)
+	}
+
+	mx := records[1].(types.Object).Attributes()
+	if !mx["name"].Equal(types.StringValue("")) || !mx["content"].Equal(types.StringValue("mail.example.com")) ||
+		!mx["prio"].Equal(types.StringValue("10")) || !mx["ttl"].Equal(types.StringValue("3600")) {
+		t.Errorf("unexpected record %v", records[1])
+	}
+
+	if _, err := runFunction(t, NewParseZoneFunction(), types.StringValue("$INCLUDE other.zone"), types.StringValue("example.com")); err == nil {
+		t.Error("expected an unsupported directive to be an error")
+	}
+}
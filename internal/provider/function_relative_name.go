@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = relativeNameFunction{}
+
+func NewRelativeNameFunction() function.Function {
+	return relativeNameFunction{}
+}
+
+type relativeNameFunction struct{}
+
+func (f relativeNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "relative_name"
+}
+
+func (f relativeNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Name of a record relative to its domain",
+		MarkdownDescription: "Returns the `name` of a record for `porkbun_dns_record` from its fully qualified name, the inverse of `fqdn`. The apex is an empty string. Only the domain at the end is removed, so `example.com.example.com` becomes `example.com`. Fails when the name isn't within the domain.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "fqdn",
+				MarkdownDescription: "The fully qualified name of the record, with or without a trailing dot",
+			},
+			function.StringParameter{
+				Name:                "domain",
+				MarkdownDescription: "The base domain of the record",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f relativeNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var fqdn, domain string
+
+	resp.Error = req.Arguments.Get(ctx, &fqdn, &domain)
+	if resp.Error != nil {
+		return
+	}
+
+	name, err := relativeRecordName(fqdn, domain)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, name)
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_RelativeNameFunction(t *testing.T) {
+	tests := []struct {
+		fqdn     string
+		domain   string
+		expected string
+	}{
+		{"example.com.example.com.", "example.com", "example.com"},
+		{"example.com", "example.com", ""},
+		{"Example.com.", "example.com.", ""},
+		{"*.dev.example.com", "example.com", "*.dev"},
+		{"www.example.com.", "example.com", "www"},
+		{"bücher.münchen.de", "xn--mnchen-3ya.de", "xn--bcher-kva"},
+		{"xn--bcher-kva.xn--mnchen-3ya.de", "münchen.de", "xn--bcher-kva"},
+	}
+
+	for _, tc := range tests {
+		result, err := runFunction(t, NewRelativeNameFunction(), types.StringValue(tc.fqdn), types.StringValue(tc.domain))
+		if err != nil || !result.Equal(types.StringValue(tc.expected)) {
+			t.Errorf("%q, %q: expected %q, got %v: %v", tc.fqdn, tc.domain, tc.expected, result, err)
+		}
+	}
+
+	_, err := runFunction(t, NewRelativeNameFunction(), types.StringValue("www.example.org"), types.StringValue("example.com"))
+	if expected := function.NewFuncError(`"www.example.org" is not within "example.com"`); !err.Equal(expected) {
+		t.Errorf("expected %v for a name outside of the domain, got %v", expected, err)
+	}
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = renderZoneFunction{}
+
+func NewRenderZoneFunction() function.Function {
+	return renderZoneFunction{}
+}
+
+type renderZoneFunction struct{}
+
+func (f renderZoneFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "render_zone"
+}
+
+func (f renderZoneFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Zone file of records",
+		MarkdownDescription: "Renders records as a zone file, the inverse of `parse_zone`. The records are objects with the `name`, `type`, `content`, `ttl` and `prio` attributes of `porkbun_dns_record`, so the resources themselves can be passed, and other attributes are ignored. " +
+			"Names are written relative to the origin the file is loaded with, host names in the content of `CNAME`, `MX`, `NS` and `SRV` records get a trailing dot and `TXT` values are split into quoted strings of at most 255 characters.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "records",
+				MarkdownDescription: "A list, set or map of records, e.g. the result of `parse_zone` or `values(porkbun_dns_record.all)`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f renderZoneFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var records types.Dynamic
+
+	resp.Error = req.Arguments.Get(ctx, &records)
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := parseZoneRecords(records.UnderlyingValue())
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	text, err := renderZoneFile(parsed)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, text)
+}
+
+// parseZoneRecords reads the records argument of render_zone. The elements of a map are taken in the order of their
+// keys.
+func parseZoneRecords(value attr.Value) ([]zoneRecord, error) {
+	var elements []attr.Value
+	switch v := value.(type) {
+	case types.Tuple:
+		elements = v.Elements()
+	case types.List:
+		elements = v.Elements()
+	case types.Set:
+		elements = v.Elements()
+	case types.Map, types.Object:
+		var byKey map[string]attr.Value
+		if m, ok := v.(types.Map); ok {
+			byKey = m.Elements()
+		} else {
+			byKey = v.(types.Object).Attributes()
+		}
+		keys := make([]string, 0, len(byKey))
+		for key := range byKey {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			elements = append(elements, byKey[key])
+		}
+	default:
+		return nil, fmt.Errorf("the records must be a list, set or map of objects")
+	}
+
+	records := make([]zoneRecord, 0, len(elements))
+	for i, element := range elements {
+		var attributes map[string]attr.Value
+		switch v := element.(type) {
+		case types.Object:
+			attributes = v.Attributes()
+		case types.Map:
+			attributes = v.Elements()
+		default:
+			return nil, fmt.Errorf("record %d must be an object with name, type and content", i)
+		}
+
+		var record zoneRecord
+		for name, target := range map[string]*string{
+			"name":    &record.name,
+			"type":    &record.typ,
+			"content": &record.content,
+			"ttl":     &record.ttl,
+			"prio":    &record.prio,
+		} {
+			value, ok := attributes[name]
+			if !ok || value.IsNull() {
+				continue
+			}
+			if value.IsUnknown() {
+				return nil, fmt.Errorf("record %d: %s must be known", i, name)
+			}
+
+			s, err := dynamicString(name, value)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %w", i, err)
+			}
+			*target = s
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_RenderZoneFunction(t *testing.T) {
+	record := map[string]attr.Type{"name": types.StringType, "type": types.StringType, "content": types.StringType, "id": types.StringType}
+	records := types.MapValueMust(types.ObjectType{AttrTypes: record}, map[string]attr.Value{
+		"b": types.ObjectValueMust(record, map[string]attr.Value{
+			"name": types.StringValue("www"), "type": types.StringValue("A"), "content": types.StringValue("192.0.2.2"), "id": types.StringValue("2"),
+		}),
+		"a": types.ObjectValueMust(record, map[string]attr.Value{
+			"name": types.StringValue(""), "type": types.StringValue("A"), "content": types.StringValue("192.0.2.1"), "id": types.StringValue("1"),
+		}),
+	})
+
+	result, err := runFunction(t, NewRenderZoneFunction(), types.DynamicValue(records))
+	if err != nil || !result.Equal(types.StringValue("@    IN A 192.0.2.1\nwww  IN A 192.0.2.2\n")) {
+		t.Errorf("unexpected result %v: %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewRenderZoneFunction(), types.DynamicValue(types.StringValue("www A 192.0.2.1"))); err == nil {
+		t.Error("expected a string to be an error")
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = spfFunction{}
+
+func NewSpfFunction() function.Function {
+	return spfFunction{}
+}
+
+type spfFunction struct{}
+
+func (f spfFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "spf"
+}
+
+func (f spfFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Content of an SPF record",
+		MarkdownDescription: "Returns the content of an SPF `TXT` record from its mechanisms and modifiers, checking their syntax. `porkbun_dns_record` warns when the record needs more than 10 DNS lookups.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "mechanisms",
+				ElementType:         types.StringType,
+				MarkdownDescription: "The mechanisms and modifiers in order, e.g. `[\"mx\", \"include:_spf.google.com\", \"ip4:192.0.2.0/24\"]`",
+			},
+			function.StringParameter{
+				Name:                "all",
+				AllowNullValue:      true,
+				MarkdownDescription: "The all mechanism ending the record, one of `-all`, `~all`, `?all` or `+all`. `null` leaves it out, e.g. when using the `redirect` modifier",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f spfFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mechanisms []string
+	var all types.String
+
+	resp.Error = req.Arguments.Get(ctx, &mechanisms, &all)
+	if resp.Error != nil {
+		return
+	}
+
+	content, err := buildSpfRecord(mechanisms, all.ValueString())
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, content)
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_SpfFunction(t *testing.T) {
+	mechanisms := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("mx"), types.StringValue("include:_spf.google.com")})
+
+	result, err := runFunction(t, NewSpfFunction(), mechanisms, types.StringValue("~all"))
+	if err != nil || !result.Equal(types.StringValue("v=spf1 mx include:_spf.google.com ~all")) {
+		t.Errorf("unexpected result %v: %v", result, err)
+	}
+
+	result, err = runFunction(t, NewSpfFunction(), mechanisms, types.StringNull())
+	if err != nil || !result.Equal(types.StringValue("v=spf1 mx include:_spf.google.com")) {
+		t.Errorf("expected the record without all, got %v: %v", result, err)
+	}
+
+	invalid := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ip4:not-an-ip")})
+	if _, err := runFunction(t, NewSpfFunction(), invalid, types.StringValue("-all")); err == nil {
+		t.Error("expected an invalid mechanism to be an error")
+	}
+}
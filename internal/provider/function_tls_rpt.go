@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = tlsRptFunction{}
+
+func NewTlsRptFunction() function.Function {
+	return tlsRptFunction{}
+}
+
+type tlsRptFunction struct{}
+
+func (f tlsRptFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "tls_rpt"
+}
+
+func (f tlsRptFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Content of a TLS-RPT record",
+		MarkdownDescription: "Returns the content of the `_smtp._tls` `TXT` record asking senders for SMTP TLS reports.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "rua",
+				ElementType:         types.StringType,
+				MarkdownDescription: "Where to send the reports, `mailto:` or `https:` URIs",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f tlsRptFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rua []string
+
+	resp.Error = req.Arguments.Get(ctx, &rua)
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateReportUris(rua, "mailto:", "https:"); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, "v=TLSRPTv1; rua="+strings.Join(rua, ","))
+}
+
+// validateReportUris checks the URIs reports are sent to, which are separated by commas in the record
+func validateReportUris(uris []string, schemes ...string) error {
+	if len(uris) == 0 {
+		return fmt.Errorf("at least one URI is needed")
+	}
+
+	for _, uri := range uris {
+		valid := false
+		for _, scheme := range schemes {
+			valid = valid || (strings.HasPrefix(strings.ToLower(uri), scheme) && len(uri) > len(scheme))
+		}
+
+		switch {
+		case !valid:
+			return fmt.Errorf("%q must be a %s URI", uri, strings.Join(schemes, " or "))
+		case strings.ContainsAny(uri, ",; \t"):
+			return fmt.Errorf("%q must not contain commas, semicolons or spaces, encode them with %%2C, %%3B and %%20", uri)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_TlsRptFunction(t *testing.T) {
+	rua := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("mailto:tls@example.com"), types.StringValue("https://reports.example.com/tls")})
+
+	result, err := runFunction(t, NewTlsRptFunction(), rua)
+	if err != nil || !result.Equal(types.StringValue("v=TLSRPTv1; rua=mailto:tls@example.com,https://reports.example.com/tls")) {
+		t.Errorf("unexpected result %v: %v", result, err)
+	}
+
+	for _, uri := range []string{"tls@example.com", "http://reports.example.com", "mailto:a@example.com,b@example.com"} {
+		invalid := types.ListValueMust(types.StringType, []attr.Value{types.StringValue(uri)})
+		if _, err := runFunction(t, NewTlsRptFunction(), invalid); err == nil {
+			t.Errorf("%q: expected an invalid URI to be an error", uri)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = toAsciiFunction{}
+
+func NewToAsciiFunction() function.Function {
+	return toAsciiFunction{}
+}
+
+type toAsciiFunction struct{}
+
+func (f toAsciiFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_ascii"
+}
+
+func (f toAsciiFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "ASCII form of a domain name",
+		MarkdownDescription: "Returns the name in the ASCII form Porkbun uses, in lowercase with internationalized labels in punycode, e.g. `bücher.münchen.de` becomes `xn--bcher-kva.xn--mnchen-3ya.de`. A trailing dot is kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The domain or record name, e.g. `bücher.münchen.de`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f toAsciiFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	converted, err := normalizeDomainName(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	if converted != "" && strings.HasSuffix(strings.TrimSpace(name), ".") {
+		converted += "."
+	}
+
+	resp.Error = resp.Result.Set(ctx, converted)
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_ToAsciiFunction(t *testing.T) {
+	result, err := runFunction(t, NewToAsciiFunction(), types.StringValue("Bücher.München.de"))
+	if err != nil || !result.Equal(types.StringValue("xn--bcher-kva.xn--mnchen-3ya.de")) {
+		t.Errorf("expected xn--bcher-kva.xn--mnchen-3ya.de, got %v: %v", result, err)
+	}
+
+	result, err = runFunction(t, NewToAsciiFunction(), types.StringValue("münchen.de."))
+	if err != nil || !result.Equal(types.StringValue("xn--mnchen-3ya.de.")) {
+		t.Errorf("expected the trailing dot to be kept, got %v: %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewToAsciiFunction(), types.StringValue("www..example.com")); err == nil {
+		t.Error("expected an empty label to be an error")
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = toUnicodeFunction{}
+
+func NewToUnicodeFunction() function.Function {
+	return toUnicodeFunction{}
+}
+
+type toUnicodeFunction struct{}
+
+func (f toUnicodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_unicode"
+}
+
+func (f toUnicodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Unicode form of a domain name",
+		MarkdownDescription: "Returns the name in lowercase with punycode labels converted to Unicode, the inverse of `to_ascii`, e.g. `xn--bcher-kva.xn--mnchen-3ya.de` becomes `bücher.münchen.de`. A trailing dot is kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The domain or record name, e.g. `xn--bcher-kva.xn--mnchen-3ya.de`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f toUnicodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	converted, err := unicodeDomainName(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	if converted != "" && strings.HasSuffix(strings.TrimSpace(name), ".") {
+		converted += "."
+	}
+
+	resp.Error = resp.Result.Set(ctx, converted)
+}
@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_ToUnicodeFunction(t *testing.T) {
+	result, err := runFunction(t, NewToUnicodeFunction(), types.StringValue("xn--bcher-kva.xn--mnchen-3ya.de"))
+	if err != nil || !result.Equal(types.StringValue("bücher.münchen.de")) {
+		t.Errorf("expected bücher.münchen.de, got %v: %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewToUnicodeFunction(), types.StringValue("xn--zz.de")); err == nil {
+		t.Error("expected invalid punycode to be an error")
+	}
+}
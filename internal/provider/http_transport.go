@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// defaultRequestTimeout bounds a single request to Porkbun, retries get their own timeout
+const defaultRequestTimeout = 30 * time.Second
+
+// newHTTPTransport builds the transport used to reach Porkbun from the proxy and TLS settings of the provider
+func newHTTPTransport(data PorkbunProviderModel) (*http.Transport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Proxies from HTTPS_PROXY and friends are used unless http_proxy is set
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if !data.HttpProxy.IsNull() {
+		proxy, err := url.Parse(data.HttpProxy.ValueString())
+		if err != nil || proxy.Host == "" {
+			diags.AddAttributeError(
+				path.Root("http_proxy"),
+				"Invalid http_proxy",
+				fmt.Sprintf("expected a URL like http://proxy.example.com:3128, got %q", data.HttpProxy.ValueString()),
+			)
+			return nil, diags
+		}
+
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			diags.AddAttributeError(
+				path.Root("http_proxy"),
+				"Invalid http_proxy",
+				fmt.Sprintf("the proxy scheme must be http, https or socks5, got %q", proxy.Scheme),
+			)
+			return nil, diags
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if !data.CaCertFile.IsNull() {
+		file, err := expandHome(data.CaCertFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("ca_cert_file"), "Unable to read ca_cert_file", err.Error())
+			return nil, diags
+		}
+
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			diags.AddAttributeError(path.Root("ca_cert_file"), "Unable to read ca_cert_file", err.Error())
+			return nil, diags
+		}
+
+		// The bundle is trusted in addition to the system roots, which Porkbun itself is signed by
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			diags.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Invalid ca_cert_file",
+				fmt.Sprintf("%s doesn't contain any PEM encoded certificates", file),
+			)
+			return nil, diags
+		}
+
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if data.InsecureSkipVerify.ValueBool() {
+		diags.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS certificate verification is disabled",
+			"The certificate of the Porkbun API isn't verified, which lets anyone in between read the API keys. Only use insecure_skip_verify with test stand-ins of the API.",
+		)
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	return transport, diags
+}
+
+// requestTimeout returns the timeout of a single request from the provider configuration
+func requestTimeout(data PorkbunProviderModel) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.RequestTimeout.IsNull() {
+		return defaultRequestTimeout, diags
+	}
+
+	timeout, err := time.ParseDuration(data.RequestTimeout.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("request_timeout"), "Invalid duration", err.Error())
+		return 0, diags
+	}
+
+	return timeout, diags
+}
+
+// userAgent identifies the provider and the Terraform version using it to Porkbun
+func userAgent(providerVersion string, terraformVersion string) string {
+	if terraformVersion == "" {
+		terraformVersion = "unknown"
+	}
+
+	return fmt.Sprintf("Terraform/%s (+https://www.terraform.io) terraform-provider-porkbun/%s", terraformVersion, providerVersion)
+}
+
+// userAgentTransport sets the User-Agent of every request
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A round tripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	return t.next.RoundTrip(req)
+}
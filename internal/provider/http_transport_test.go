@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func transportProviderModel() PorkbunProviderModel {
+	return PorkbunProviderModel{
+		HttpProxy:          types.StringNull(),
+		CaCertFile:         types.StringNull(),
+		InsecureSkipVerify: types.BoolNull(),
+		RequestTimeout:     types.StringNull(),
+	}
+}
+
+func Test_HTTPTransportTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("User-Agent")))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(data PorkbunProviderModel) error {
+		transport, diags := newHTTPTransport(data)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		client := &http.Client{Transport: &userAgentTransport{userAgent: userAgent("test", "1.9.0"), next: transport}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		return nil
+	}
+
+	if err := get(transportProviderModel()); err == nil {
+		t.Error("expected the self-signed certificate to be rejected")
+	}
+
+	data := transportProviderModel()
+	data.CaCertFile = types.StringValue(caFile)
+	if err := get(data); err != nil {
+		t.Errorf("expected the certificate to be trusted with ca_cert_file: %s", err)
+	}
+
+	data = transportProviderModel()
+	data.InsecureSkipVerify = types.BoolValue(true)
+	if _, diags := newHTTPTransport(data); diags.WarningsCount() != 1 {
+		t.Errorf("expected a warning for insecure_skip_verify, got %v", diags)
+	}
+	if err := get(data); err != nil {
+		t.Errorf("expected the certificate not to be verified with insecure_skip_verify: %s", err)
+	}
+
+	data = transportProviderModel()
+	data.CaCertFile = types.StringValue(filepath.Join(t.TempDir(), "missing.pem"))
+	if _, diags := newHTTPTransport(data); !diags.HasError() {
+		t.Error("expected a missing ca_cert_file to be an error")
+	}
+}
+
+func Test_HTTPTransportProxy(t *testing.T) {
+	data := transportProviderModel()
+	data.HttpProxy = types.StringValue("http://proxy.example.com:3128")
+
+	transport, diags := newHTTPTransport(data)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.porkbun.com/api/json/v3/ping", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.example.com:3128" {
+		t.Errorf("expected requests to go through the proxy, got %v (%v)", proxy, err)
+	}
+
+	data.HttpProxy = types.StringValue("ftp://proxy.example.com")
+	if _, diags := newHTTPTransport(data); !diags.HasError() {
+		t.Error("expected an unsupported proxy scheme to be an error")
+	}
+}
+
+func Test_UserAgent(t *testing.T) {
+	expected := "Terraform/1.9.0 (+https://www.terraform.io) terraform-provider-porkbun/0.3.0"
+	if actual := userAgent("0.3.0", "1.9.0"); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &userAgentTransport{userAgent: expected, next: http.DefaultTransport}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if received != expected {
+		t.Errorf("expected the User-Agent %q to be sent, got %q", expected, received)
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// redactedValue replaces the keys in logged request bodies
+	redactedValue = "***"
+
+	// maxLoggedBodySize limits how much of a body that isn't JSON is logged
+	maxLoggedBodySize = 1024
+)
+
+// redactedBodyFields are the fields of request bodies holding the credentials
+var redactedBodyFields = []string{"apikey", "secretapikey"}
+
+// loggingTransport logs every request to the Porkbun API at trace level, including every retry, with the keys in
+// the request bodies masked
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	fields := map[string]interface{}{
+		"method":   req.Method,
+		"endpoint": req.URL.Path,
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// A round tripper must not modify the request it was given
+		req = req.Clone(ctx)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		fields["request_body"] = redactBody(body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Trace(ctx, "Porkbun API request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Trace(ctx, "Porkbun API request failed", fields)
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	fields["response_body"] = redactBody(body)
+
+	tflog.Trace(ctx, "Porkbun API request", fields)
+
+	return resp, nil
+}
+
+// redactBody renders a body for the logs with the credentials masked. A body that isn't JSON is only shown when it
+// can't contain the credentials, which are only ever sent as JSON.
+func redactBody(body []byte) string {
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		if bytes.Contains(bytes.ToLower(body), []byte("apikey")) {
+			return fmt.Sprintf("<%d bytes that aren't JSON>", len(body))
+		}
+
+		if len(body) > maxLoggedBodySize {
+			return string(body[:maxLoggedBodySize]) + "..."
+		}
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isRedactedField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+
+	return value
+}
+
+func isRedactedField(key string) bool {
+	for _, field := range redactedBodyFields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+
+	return false
+}
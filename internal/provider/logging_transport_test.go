@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func Test_LoggingTransportRedactsKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "SUCCESS", "yourIp": "127.0.0.1"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	body := `{"apikey": "pk1_secret", "secretapikey": "sk1_secret", "name": "www"}`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/json/v3/ping", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := &loggingTransport{next: http.DefaultTransport}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The response body is still readable after logging it
+	respBody, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(respBody), "127.0.0.1") {
+		t.Errorf("unexpected response body %s", respBody)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected a single log entry, got %v", entries)
+	}
+
+	entry := entries[0]
+	if entry["@level"] != "trace" || entry["endpoint"] != "/api/json/v3/ping" || entry["status"] != float64(200) {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+
+	if strings.Contains(output.String(), "_secret") {
+		t.Error("expected the keys to be redacted")
+	}
+
+	if entry["request_body"] != `{"apikey":"***","name":"www","secretapikey":"***"}` {
+		t.Errorf("unexpected request body %v", entry["request_body"])
+	}
+}
+
+func Test_RedactBody(t *testing.T) {
+	tests := map[string]string{
+		`{"apiKey": "pk1_a", "records": [{"secretapikey": "sk1_a"}]}`: `{"apiKey":"***","records":[{"secretapikey":"***"}]}`,
+		`apikey=pk1_a&secretapikey=sk1_a`:                             `<31 bytes that aren't JSON>`,
+		`<html>Service Unavailable</html>`:                            `<html>Service Unavailable</html>`,
+	}
+
+	for body, expected := range tests {
+		if actual := redactBody([]byte(body)); actual != expected {
+			t.Errorf("%s: expected %s, got %s", body, expected, actual)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"strconv"
@@ -10,29 +11,51 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/nrdcg/porkbun"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ provider.Provider = &porkbunProvider{}
+var _ provider.ProviderWithFunctions = &porkbunProvider{}
 
 type porkbunProvider struct {
 	client     *porkbun.Client
+	records    *recordCache
+	limiter    *rateLimiter
+	retry      retryPolicy
 	configured bool
 	version    string
-	MaxRetries int
+	// baseUrl is used when neither base_url nor PORKBUN_BASE_URL are set, tests point it at a fake API
+	baseUrl string
+	// wrapTransport wraps the transport reaching Porkbun, tests record and replay the traffic with it
+	wrapTransport func(http.RoundTripper) http.RoundTripper
+	MaxRetries    int
 }
 
 // providerData can be used to store data from the Terraform configuration.
 type PorkbunProviderModel struct {
-	ApiKey     types.String `tfsdk:"api_key"`
-	SecretKey  types.String `tfsdk:"secret_key"`
-	BaseUrl    types.String `tfsdk:"base_url"`
-	MaxRetries types.Int64  `tfsdk:"max_retries"`
+	ApiKey              types.String       `tfsdk:"api_key"`
+	SecretKey           types.String       `tfsdk:"secret_key"`
+	CredentialsFile     types.String       `tfsdk:"credentials_file"`
+	Profile             types.String       `tfsdk:"profile"`
+	CredentialProcess   types.String       `tfsdk:"credential_process"`
+	ValidateCredentials types.Bool         `tfsdk:"validate_credentials"`
+	HttpProxy           types.String       `tfsdk:"http_proxy"`
+	CaCertFile          types.String       `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify  types.Bool         `tfsdk:"insecure_skip_verify"`
+	RequestTimeout      types.String       `tfsdk:"request_timeout"`
+	BaseUrl             types.String       `tfsdk:"base_url"`
+	MaxRetries          types.Int64        `tfsdk:"max_retries"`
+	RequestsPerSecond   types.Float64      `tfsdk:"requests_per_second"`
+	Burst               types.Int64        `tfsdk:"burst"`
+	Retry               *porkbunRetryModel `tfsdk:"retry"`
 }
 
 func (p *porkbunProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -53,7 +76,6 @@ func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	var apiKey string
 	if data.ApiKey.IsUnknown() {
 		// Cannot connect to client with an unknown value
 		resp.Diagnostics.AddWarning(
@@ -63,22 +85,6 @@ func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	apiKey = data.ApiKey.ValueString()
-
-	if data.ApiKey.IsNull() {
-		apiKey = os.Getenv("PORKBUN_API_KEY")
-	}
-
-	if apiKey == "" {
-		// Error vs warning - empty value must stop execution
-		resp.Diagnostics.AddError(
-			"Unable to find api_key",
-			"api_key cannot be an empty string",
-		)
-		return
-	}
-
-	var secretKey string
 	if data.SecretKey.IsUnknown() {
 		// Cannot connect to client with an unknown value
 		resp.Diagnostics.AddWarning(
@@ -88,25 +94,42 @@ func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	secretKey = data.SecretKey.ValueString()
+	creds, diags := resolveCredentials(ctx, data)
+	resp.Diagnostics.Append(diags...)
 
-	if data.SecretKey.IsNull() {
-		secretKey = os.Getenv("PORKBUN_SECRET_KEY")
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if secretKey == "" {
-		// Error vs warning - empty value must stop execution
-		resp.Diagnostics.AddError(
-			"Unable to find secret_key",
-			"secret_key cannot be an empty string",
-		)
-		return
+	validateCredentials := data.ValidateCredentials.IsNull() || data.ValidateCredentials.ValueBool()
+	if validateCredentials {
+		resp.Diagnostics.Append(validateKeyFormat(creds)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	c := porkbun.New(secretKey, apiKey)
+	c := porkbun.New(creds.secretKey, creds.apiKey)
+
+	baseUrl := p.baseUrl
+	if !data.BaseUrl.IsNull() {
+		baseUrl = data.BaseUrl.ValueString()
+	} else if env, ok := os.LookupEnv("PORKBUN_BASE_URL"); ok {
+		baseUrl = env
+	}
 
-	if baseUrl, ok := os.LookupEnv("PORKBUN_BASE_URL"); ok {
-		c.BaseURL, _ = url.Parse(baseUrl)
+	if baseUrl != "" {
+		parsed, err := url.Parse(baseUrl)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("base_url"),
+				"Invalid base_url",
+				err.Error(),
+			)
+			return
+		}
+		c.BaseURL = parsed
 	}
 
 	if data.MaxRetries.IsNull() {
@@ -117,6 +140,7 @@ func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 					"failed converting max retries",
 					err.Error(),
 				)
+				return
 			}
 			p.MaxRetries = mri
 		} else {
@@ -126,13 +150,94 @@ func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 		p.MaxRetries = int(data.MaxRetries.ValueInt64())
 	}
 
+	requestsPerSecond := defaultRequestsPerSecond
+	if !data.RequestsPerSecond.IsNull() {
+		requestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	}
+
+	if requestsPerSecond <= 0 {
+		resp.Diagnostics.AddError(
+			"Invalid requests_per_second",
+			fmt.Sprintf("requests_per_second must be greater than 0, got %v", requestsPerSecond),
+		)
+		return
+	}
+
+	burst := defaultBurst
+	if !data.Burst.IsNull() {
+		burst = int(data.Burst.ValueInt64())
+	}
+
+	if burst < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid burst",
+			fmt.Sprintf("burst must be at least 1, got %d", burst),
+		)
+		return
+	}
+
+	retry, diags := newRetryPolicy(data.Retry)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.retry = retry
+
+	transport, diags := newHTTPTransport(data)
+	resp.Diagnostics.Append(diags...)
+	timeout, diags := requestTimeout(data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var base http.RoundTripper = transport
+	if p.wrapTransport != nil {
+		base = p.wrapTransport(transport)
+	}
+
+	key := accountKey(c.BaseURL.String(), creds.apiKey, creds.secretKey)
+	limited := &rateLimitedTransport{
+		limiter: sharedLimiter(key, requestsPerSecond, burst),
+		next: &userAgentTransport{
+			userAgent: userAgent(p.version, req.TerraformVersion),
+			next:      &loggingTransport{next: base},
+		},
+	}
+
 	retryClient := retryablehttp.NewClient()
 	retryClient.RetryMax = p.MaxRetries
+	p.retry.configure(retryClient)
+	retryClient.HTTPClient.Timeout = timeout
+	retryClient.HTTPClient.Transport = limited
 	c.HTTPClient = retryClient.StandardClient()
 
+	if validateCredentials {
+		pingCtx, cancel := p.retry.operationContext(ctx, operationRead)
+		defer cancel()
+
+		resp.Diagnostics.Append(pingCredentials(pingCtx, c, creds)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Only a configured instance joins the account, so a failed configuration doesn't leave a budget behind. Another
+	// instance may have joined in the meantime, nothing was sent since the ping so the limiter can still be switched.
+	account, diags := joinSharedAccount(ctx, p, key, limited.limiter, newRecordCache(), requestsPerSecond, burst)
+	resp.Diagnostics.Append(diags...)
+
+	limited.limiter = account.limiter
+	p.limiter = account.limiter
+	p.client = c
+	p.records = account.records
 	p.configured = true
-	resp.DataSourceData = c
-	resp.ResourceData = c
+	resp.DataSourceData = p
+	resp.ResourceData = p
 }
 
 func (p *porkbunProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -145,6 +250,23 @@ func (p *porkbunProvider) DataSources(ctx context.Context) []func() datasource.D
 	return []func() datasource.DataSource{}
 }
 
+func (p *porkbunProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewFqdnFunction,
+		NewRelativeNameFunction,
+		NewToAsciiFunction,
+		NewToUnicodeFunction,
+		NewSpfFunction,
+		NewDmarcFunction,
+		NewMtaStsFunction,
+		NewTlsRptFunction,
+		NewDkimFunction,
+		NewDsFunction,
+		NewParseZoneFunction,
+		NewRenderZoneFunction,
+	}
+}
+
 func (p *porkbunProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
@@ -160,6 +282,47 @@ func (p *porkbunProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file with named profiles of Porkbun credentials, defaults to `~/.config/porkbun/credentials`. Can also be set with the `PORKBUN_CREDENTIALS_FILE` environment variable",
+				Required:            false,
+				Optional:            true,
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "The profile of the credentials file to use when `api_key` and `secret_key` aren't set, defaults to `default`. A selected profile takes precedence over the `PORKBUN_API_KEY` and `PORKBUN_SECRET_KEY` environment variables, and a profile has to set both keys. Can also be set with the `PORKBUN_PROFILE` environment variable",
+				Required:            false,
+				Optional:            true,
+			},
+			"credential_process": schema.StringAttribute{
+				MarkdownDescription: "A command that prints the credentials as JSON, e.g. `{\"api_key\": \"pk1_...\", \"secret_key\": \"sk1_...\"}`, used when `api_key` and `secret_key` aren't set. Can also be set as `credential_process` in a profile of the credentials file. The command runs once per plugin process and its output is never logged",
+				Required:            false,
+				Optional:            true,
+			},
+			"validate_credentials": schema.BoolAttribute{
+				MarkdownDescription: "Check the format of the keys and that Porkbun accepts them with a call to the ping endpoint when the provider is configured, defaults to `true`",
+				Required:            false,
+				Optional:            true,
+			},
+			"http_proxy": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy to reach Porkbun through, e.g. `http://proxy.example.com:3128`. Defaults to the proxy from the `HTTPS_PROXY` environment variable",
+				Required:            false,
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM encoded CA bundle to trust in addition to the system roots, e.g. for a proxy that inspects TLS",
+				Required:            false,
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Don't verify the TLS certificate of the Porkbun API. Only meant for test stand-ins of the API, defaults to `false`",
+				Required:            false,
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time a single request to the Porkbun API may take before it is retried, defaults to `30s`",
+				Required:            false,
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
 			"base_url": schema.StringAttribute{
 				MarkdownDescription: "Override Porkbun Base URL",
 				Required:            false,
@@ -170,6 +333,19 @@ func (p *porkbunProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Required:            false,
 				Optional:            true,
 			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of requests per second sent to the Porkbun API by all resources, defaults to 2. Provider configurations using the same API and secret key share this budget",
+				Required:            false,
+				Optional:            true,
+			},
+			"burst": schema.Int64Attribute{
+				MarkdownDescription: "Number of requests that can be sent at once before `requests_per_second` applies, defaults to 5",
+				Required:            false,
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": retryBlock(),
 		},
 	}
 }
@@ -1,30 +1,237 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
 
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/nrdcg/porkbun"
 )
 
+const (
+	// testDomain is the zone the acceptance tests create records in
+	testDomain = "providertest.top"
+
+	// cassetteDir holds the traffic of the acceptance tests recorded from the real Porkbun API
+	cassetteDir = "testdata/cassettes"
+)
+
 func newPorkbunProvider(testUrl string) provider.Provider {
-	client := porkbun.New("sk1_foobarbaz", "pk1_foobarbaz")
-	client.BaseURL, _ = url.Parse(testUrl)
 	return &porkbunProvider{
-		client:     client,
-		configured: true,
-		version:    "test",
+		baseUrl: testUrl,
+		version: "test",
 	}
 }
 
-var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"porkbun": providerserver.NewProtocol6WithError(New("test")()),
-}
-
 func protoV6ProviderFactories(url string) map[string]func() (tfprotov6.ProviderServer, error) {
 	return map[string]func() (tfprotov6.ProviderServer, error){
 		"porkbun": providerserver.NewProtocol6WithError(newPorkbunProvider(url)),
 	}
 }
+
+// newTestServer starts a fake Porkbun API serving the test domain and provides its keys to the provider, both
+// through the environment and as the api_key and secret_key variables. PORKBUN_BASE_URL points at the fake API as
+// well, so a base URL set in the environment doesn't take precedence over it.
+func newTestServer(t *testing.T) *porkbuntest.Server {
+	server := porkbuntest.NewServer(testDomain)
+	t.Cleanup(server.Close)
+
+	t.Setenv("PORKBUN_BASE_URL", server.URL())
+	t.Setenv("PORKBUN_API_KEY", porkbuntest.APIKey)
+	t.Setenv("PORKBUN_SECRET_KEY", porkbuntest.SecretKey)
+	t.Setenv("TF_VAR_api_key", porkbuntest.APIKey)
+	t.Setenv("TF_VAR_secret_key", porkbuntest.SecretKey)
+
+	return server
+}
+
+// testAccCheckRecordsDestroyed checks that no records are left in the test domain of the fake API
+func testAccCheckRecordsDestroyed(server *porkbuntest.Server) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		if records := server.Records(testDomain); len(records) != 0 {
+			return fmt.Errorf("expected all records to be destroyed, found %v", records)
+		}
+
+		return nil
+	}
+}
+
+// accMode is what the acceptance tests with cassettes run against
+type accMode int
+
+const (
+	// accModeFake runs the test against the fake API
+	accModeFake accMode = iota
+	// accModeReplay replays the cassette of the test
+	accModeReplay
+	// accModeRecord runs the test against the real Porkbun API and records its traffic to the cassette
+	accModeRecord
+)
+
+// accTestMode selects the mode of a test: recording with PORKBUN_RECORD=1, otherwise replaying its cassette when
+// there is one and running it against the fake API when there isn't
+func accTestMode(file string) accMode {
+	if os.Getenv("PORKBUN_RECORD") == "1" {
+		return accModeRecord
+	}
+
+	if _, err := os.Stat(file); err == nil {
+		return accModeReplay
+	}
+
+	return accModeFake
+}
+
+// accTest is what an acceptance test runs against, see accTestMode
+type accTest struct {
+	factories map[string]func() (tfprotov6.ProviderServer, error)
+	client    *porkbun.Client
+	rand      *rand.Rand
+}
+
+func newAccTest(t *testing.T) *accTest {
+	file := filepath.Join(cassetteDir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+
+	switch accTestMode(file) {
+	case accModeRecord:
+		return newRecordAccTest(t, file)
+	case accModeReplay:
+		return newReplayAccTest(t, file)
+	default:
+		return newFakeAccTest(t)
+	}
+}
+
+// newRecordAccTest records the traffic to the real Porkbun API using the keys from the environment. Cassettes of
+// any other API would only replay its behavior, so PORKBUN_BASE_URL must not be set.
+func newRecordAccTest(t *testing.T, file string) *accTest {
+	apiKey, secretKey := os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_KEY")
+	if apiKey == "" || secretKey == "" {
+		t.Fatal("PORKBUN_API_KEY and PORKBUN_SECRET_KEY must be set to record cassettes")
+	}
+	if os.Getenv("PORKBUN_BASE_URL") != "" {
+		t.Fatal("cassettes are recorded from the real Porkbun API, PORKBUN_BASE_URL must not be set")
+	}
+	t.Setenv("TF_VAR_api_key", apiKey)
+	t.Setenv("TF_VAR_secret_key", secretKey)
+
+	cassette := porkbuntest.NewRecorder(file, cleanhttp.DefaultPooledTransport(), time.Now().UnixNano())
+	t.Cleanup(func() {
+		// Only complete recordings are worth replaying
+		if t.Failed() || t.Skipped() {
+			return
+		}
+		if err := cassette.Save(); err != nil {
+			t.Errorf("saving cassette: %s", err)
+		}
+	})
+
+	return newCassetteAccTest(porkbun.New(secretKey, apiKey), cassette)
+}
+
+// newReplayAccTest replays the cassette in the file. Replaying never reaches an API, so the base URL doesn't
+// matter and the keys only have to look valid.
+func newReplayAccTest(t *testing.T, file string) *accTest {
+	cassette, err := porkbuntest.LoadCassette(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PORKBUN_API_KEY", porkbuntest.APIKey)
+	t.Setenv("PORKBUN_SECRET_KEY", porkbuntest.SecretKey)
+	t.Setenv("TF_VAR_api_key", porkbuntest.APIKey)
+	t.Setenv("TF_VAR_secret_key", porkbuntest.SecretKey)
+
+	return newCassetteAccTest(porkbun.New(porkbuntest.SecretKey, porkbuntest.APIKey), cassette)
+}
+
+// newCassetteAccTest sends the traffic of the provider and of the client checking the records through the cassette
+func newCassetteAccTest(client *porkbun.Client, cassette *porkbuntest.Cassette) *accTest {
+	client.HTTPClient = &http.Client{Transport: cassette}
+
+	p := &porkbunProvider{
+		version:       "test",
+		wrapTransport: func(http.RoundTripper) http.RoundTripper { return cassette },
+	}
+
+	return &accTest{
+		factories: map[string]func() (tfprotov6.ProviderServer, error){
+			"porkbun": providerserver.NewProtocol6WithError(p),
+		},
+		client: client,
+		rand:   rand.New(rand.NewSource(cassette.Seed)),
+	}
+}
+
+func newFakeAccTest(t *testing.T) *accTest {
+	server := newTestServer(t)
+
+	client := porkbun.New(porkbuntest.SecretKey, porkbuntest.APIKey)
+	client.BaseURL, _ = url.Parse(server.URL())
+
+	return &accTest{
+		factories: protoV6ProviderFactories(server.URL()),
+		client:    client,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// randomOctet returns a random octet for the names and contents of records, which is the same when replaying
+func (a *accTest) randomOctet() int {
+	return a.rand.Intn(255)
+}
+
+// checkDestroy checks that the records of the state are gone
+func (a *accTest) checkDestroy(s *terraform.State) error {
+	records, err := a.client.RetrieveRecords(context.Background(), testDomain)
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "porkbun_dns_record" {
+			continue
+		}
+
+		for _, record := range records {
+			if record.ID == rs.Primary.ID {
+				return fmt.Errorf("record %s still exists", record.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runFunction calls the provider function with the arguments like Terraform does and returns its result
+func runFunction(t *testing.T, f function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+
+	definition := &function.DefinitionResponse{}
+	f.Definition(context.Background(), function.DefinitionRequest{}, definition)
+
+	result, err := definition.Definition.Return.NewResultData(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := &function.RunResponse{Result: result}
+	f.Run(context.Background(), function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+
+	return resp.Result.Value(), resp.Error
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestsPerSecond = 2.0
+	defaultBurst             = 5
+
+	// defaultRateLimitPause is how long the bucket is paused when Porkbun rate limits a request without a Retry-After
+	defaultRateLimitPause = 2 * time.Second
+)
+
+// rateLimiter is a token bucket shared by every request the provider sends. Requests reserve a token and wait
+// until it becomes available, and a rate limited response pauses the bucket for all of them.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or the context is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+
+	ready := now
+	if l.pausedUntil.After(ready) {
+		ready = l.pausedUntil
+	}
+	if l.tokens < 0 {
+		ready = ready.Add(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	}
+	l.mu.Unlock()
+
+	if err := sleepUntil(ctx, ready); err != nil {
+		return err
+	}
+
+	// The bucket may have been paused while waiting for the token
+	for {
+		l.mu.Lock()
+		pausedUntil := l.pausedUntil
+		l.mu.Unlock()
+
+		if !pausedUntil.After(time.Now()) {
+			return nil
+		}
+
+		if err := sleepUntil(ctx, pausedUntil); err != nil {
+			return err
+		}
+	}
+}
+
+// Pause stops all requests until the given time, tokens don't accumulate while paused
+func (l *rateLimiter) Pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.refill(time.Now())
+		l.pausedUntil = until
+		l.last = until
+		l.tokens = min(l.tokens, 0)
+	}
+}
+
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitedTransport sends every request through the rate limiter, including the retries of the retrying client
+type rateLimitedTransport struct {
+	limiter *rateLimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Porkbun answers with a 503 when it rate limits a request
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		t.limiter.Pause(time.Now().Add(retryAfter(resp, defaultRateLimitPause)))
+	}
+
+	return resp, nil
+}
+
+// retryAfter returns how long the Retry-After header of the response asks to wait, or the fallback without one
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return fallback
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0)
+	}
+
+	return fallback
+}
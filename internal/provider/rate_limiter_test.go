@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_RateLimiterBurstAndRate(t *testing.T) {
+	limiter := newRateLimiter(20, 2)
+	start := time.Now()
+
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two requests fit in the burst, the other two wait for 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests beyond the burst to be delayed, took %s", elapsed)
+	}
+}
+
+func Test_RateLimiterWaitHonoursContext(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+	limiter.Pause(time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("expected waiting on a paused limiter to stop with the context")
+	}
+}
+
+func Test_RateLimitedTransportPausesOnRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &rateLimitedTransport{
+		limiter: newRateLimiter(100, 10),
+		next:    http.DefaultTransport,
+	}}
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the second request to wait for Retry-After, took %s", elapsed)
+	}
+}
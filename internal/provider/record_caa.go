@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	caaTags = []string{"issue", "issuewild", "iodef"}
+
+	// caaParameterRegexp matches a single issuer parameter as described in RFC 8659 section 4.2
+	caaParameterRegexp = regexp.MustCompile(`^[a-zA-Z0-9]+=[\x21-\x3A\x3C-\x7E]*$`)
+	domainLabelRegexp  = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+)
+
+// porkbunCaaModel describes the structured form of a CAA record
+type porkbunCaaModel struct {
+	Flags types.Int64  `tfsdk:"flags"`
+	Tag   types.String `tfsdk:"tag"`
+	Value types.String `tfsdk:"value"`
+}
+
+func caaBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Structured content for a `CAA` record. When set, `content` is computed from this block and must not be set",
+		Attributes: map[string]schema.Attribute{
+			"flags": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The flags of the record, `128` marks the property as critical",
+				Default:             int64default.StaticInt64(0),
+			},
+			"tag": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The property tag, one of `issue`, `issuewild` or `iodef`. Tags are case-insensitive",
+				Validators: []validator.String{
+					OneOfCaseInsensitive(caaTags...),
+				},
+			},
+			"value": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The property value without quotes, e.g. `letsencrypt.org` or `mailto:security@example.com`",
+			},
+		},
+	}
+}
+
+// isKnown reports whether every attribute of the block is known so the content can be rendered
+func (m *porkbunCaaModel) isKnown() bool {
+	return !m.Flags.IsUnknown() && !m.Tag.IsUnknown() && !m.Value.IsUnknown()
+}
+
+// validate checks the block, reporting problems against the attribute at p
+func (m *porkbunCaaModel) validate(p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Attributes of a single nested block cannot be marked as required, so check them here
+	if m.Tag.IsNull() {
+		diags.AddAttributeError(p.AtName("tag"), "Missing CAA tag", "tag must be set in the caa block")
+	}
+
+	if m.Value.IsNull() {
+		diags.AddAttributeError(p.AtName("value"), "Missing CAA value", "value must be set in the caa block")
+	}
+
+	if !m.Flags.IsUnknown() && !m.Flags.IsNull() {
+		if flags := m.Flags.ValueInt64(); flags < 0 || flags > 255 {
+			diags.AddAttributeError(p.AtName("flags"), "invalid value for flags", fmt.Sprintf("flags must be between 0 and 255, got %d", flags))
+		}
+	}
+
+	if m.Tag.IsUnknown() || m.Tag.IsNull() || m.Value.IsUnknown() || m.Value.IsNull() {
+		return diags
+	}
+
+	if err := validateCaaValue(m.Tag.ValueString(), m.Value.ValueString()); err != nil {
+		diags.AddAttributeError(p.AtName("value"), "invalid value for CAA record", err.Error())
+	}
+
+	return diags
+}
+
+// content renders the block in the canonical presentation format, e.g. `0 issue "letsencrypt.org"`
+func (m *porkbunCaaModel) content() string {
+	return formatCaaContent(m.Flags.ValueInt64(), m.Tag.ValueString(), m.Value.ValueString())
+}
+
+func formatCaaContent(flags int64, tag string, value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return fmt.Sprintf(`%d %s "%s"`, flags, strings.ToLower(tag), value)
+}
+
+// parseCaaContent parses CAA content as returned by Porkbun. The value may or may not be quoted
+// depending on how the record was created, so both forms are accepted.
+func parseCaaContent(content string) (*porkbunCaaModel, error) {
+	flagsField, rest, _ := strings.Cut(strings.TrimSpace(content), " ")
+	tag, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok {
+		return nil, fmt.Errorf("expected `<flags> <tag> <value>`, got %q", content)
+	}
+
+	flags, err := strconv.ParseInt(flagsField, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flags in %q: %s", content, err)
+	}
+
+	value = strings.TrimSpace(value)
+	for len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	value = strings.ReplaceAll(value, `\"`, `"`)
+	value = strings.ReplaceAll(value, `\\`, `\`)
+
+	return &porkbunCaaModel{
+		Flags: types.Int64Value(flags),
+		Tag:   types.StringValue(strings.ToLower(tag)),
+		Value: types.StringValue(value),
+	}, nil
+}
+
+// caaContentEqual reports whether two CAA contents only differ in quoting and whitespace
+func caaContentEqual(a string, b string) bool {
+	ca, err := parseCaaContent(a)
+	if err != nil {
+		return false
+	}
+
+	cb, err := parseCaaContent(b)
+	if err != nil {
+		return false
+	}
+
+	return ca.content() == cb.content()
+}
+
+func validateCaaValue(tag string, value string) error {
+	switch strings.ToLower(tag) {
+	case "issue", "issuewild":
+		// An empty issuer domain name (e.g. `;`) forbids issuance entirely
+		issuer, parameters, _ := strings.Cut(value, ";")
+		issuer = strings.TrimSpace(issuer)
+		if issuer != "" {
+			if err := validateDomainName(issuer); err != nil {
+				return fmt.Errorf("invalid issuer domain name %q: %s", issuer, err)
+			}
+		}
+
+		for _, parameter := range strings.Split(parameters, ";") {
+			parameter = strings.TrimSpace(parameter)
+			if parameter == "" {
+				continue
+			}
+			if !caaParameterRegexp.MatchString(parameter) {
+				return fmt.Errorf("invalid issuer parameter %q, expected `key=value`", parameter)
+			}
+		}
+	case "iodef":
+		u, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid iodef URL %q: %s", value, err)
+		}
+
+		switch u.Scheme {
+		case "mailto":
+			if u.Opaque == "" {
+				return fmt.Errorf("iodef URL %q is missing an email address", value)
+			}
+		case "http", "https":
+			if u.Host == "" {
+				return fmt.Errorf("iodef URL %q is missing a host", value)
+			}
+		default:
+			return fmt.Errorf("iodef URL %q must use the mailto, http or https scheme", value)
+		}
+	}
+
+	return nil
+}
+
+func validateDomainName(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) > 253 {
+		return fmt.Errorf("name is longer than 253 characters")
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if !domainLabelRegexp.MatchString(label) {
+			return fmt.Errorf("label %q is not a valid hostname label", label)
+		}
+	}
+
+	return nil
+}
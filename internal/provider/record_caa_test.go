@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_ParseCaaContent(t *testing.T) {
+	tests := map[string]string{
+		`0 issue "letsencrypt.org"`:                  `0 issue "letsencrypt.org"`,
+		`0 issue letsencrypt.org`:                    `0 issue "letsencrypt.org"`,
+		`0 ISSUE ""letsencrypt.org""`:                `0 issue "letsencrypt.org"`,
+		`128 iodef "mailto:security@example.com"`:    `128 iodef "mailto:security@example.com"`,
+		`0 issuewild ";"`:                            `0 issuewild ";"`,
+		`0 issue "ca.example.net; account=230123"`:   `0 issue "ca.example.net; account=230123"`,
+		`0  issue   ca.example.net; account=230123 `: `0 issue "ca.example.net; account=230123"`,
+	}
+
+	for content, expected := range tests {
+		caa, err := parseCaaContent(content)
+		if err != nil {
+			t.Errorf("parsing %q: %s", content, err)
+			continue
+		}
+
+		if got := caa.content(); got != expected {
+			t.Errorf("parsing %q: expected %q, got %q", content, expected, got)
+		}
+	}
+
+	for _, content := range []string{"", "0 issue", "zero issue letsencrypt.org"} {
+		if _, err := parseCaaContent(content); err == nil {
+			t.Errorf("expected an error parsing %q", content)
+		}
+	}
+}
+
+func Test_ValidateCaaValue(t *testing.T) {
+	valid := [][2]string{
+		{"issue", "letsencrypt.org"},
+		{"issue", ";"},
+		{"issuewild", "ca.example.net; account=230123; policy=ev"},
+		{"iodef", "mailto:security@example.com"},
+		{"iodef", "https://iodef.example.com/report"},
+	}
+
+	for _, tc := range valid {
+		if err := validateCaaValue(tc[0], tc[1]); err != nil {
+			t.Errorf("expected %s %q to be valid: %s", tc[0], tc[1], err)
+		}
+	}
+
+	invalid := [][2]string{
+		{"issue", "not a domain"},
+		{"issue", "letsencrypt.org; account"},
+		{"iodef", "ftp://iodef.example.com"},
+		{"iodef", "mailto:"},
+		{"iodef", "https:///report"},
+	}
+
+	for _, tc := range invalid {
+		if err := validateCaaValue(tc[0], tc[1]); err == nil {
+			t.Errorf("expected %s %q to be invalid", tc[0], tc[1])
+		}
+	}
+}
+
+func Test_CaaTagIsCaseInsensitive(t *testing.T) {
+	caa := porkbunCaaModel{Flags: types.Int64Value(0), Tag: types.StringValue("IssueWild"), Value: types.StringValue("letsencrypt.org")}
+	if got := caa.content(); got != `0 issuewild "letsencrypt.org"` {
+		t.Errorf("expected the tag in lowercase, got %q", got)
+	}
+
+	for _, tag := range []string{"issue", "Issue", "IODEF"} {
+		resp := &validator.StringResponse{}
+		OneOfCaseInsensitive(caaTags...).ValidateString(context.Background(), validator.StringRequest{ConfigValue: types.StringValue(tag)}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("expected %q to be a valid tag: %v", tag, resp.Diagnostics)
+		}
+	}
+
+	resp := &validator.StringResponse{}
+	OneOfCaseInsensitive(caaTags...).ValidateString(context.Background(), validator.StringRequest{ConfigValue: types.StringValue("issuer")}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an unknown tag to be invalid")
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nrdcg/porkbun"
+	"golang.org/x/sync/singleflight"
+)
+
+// recordRetrievalTimeout bounds a retrieval shared by several resources, which doesn't stop when the resource
+// that started it is cancelled
+const recordRetrievalTimeout = 5 * time.Minute
+
+// recordCache shares the records of a domain between all porkbun_dns_record resources of an account, so a refresh
+// only retrieves each domain once instead of once per record. The records of a domain are kept for a single refresh,
+// and a domain is dropped whenever one of its records is written.
+type recordCache struct {
+	group singleflight.Group
+
+	mu          sync.Mutex
+	records     map[string][]porkbun.Record
+	readers     map[string]map[string]bool
+	generations map[string]uint64
+}
+
+func newRecordCache() *recordCache {
+	return &recordCache{
+		records:     map[string][]porkbun.Record{},
+		readers:     map[string]map[string]bool{},
+		generations: map[string]uint64{},
+	}
+}
+
+// Records returns all records of the domain, retrieving them once for concurrent callers. The record with the
+// ID is refreshed from them, when it reads the domain again a new refresh has started and the records are
+// retrieved again. Lookups that aren't part of a refresh pass an empty ID. The records are retrieved with the client
+// of the caller, which uses the keys of the account. The returned slice is shared and must not be modified.
+func (c *recordCache) Records(ctx context.Context, client *porkbun.Client, domain string, id string) ([]porkbun.Record, error) {
+	c.mu.Lock()
+	records, ok := c.records[domain]
+	if ok && id != "" {
+		if c.readers[domain][id] {
+			c.invalidate(domain)
+			ok = false
+		} else {
+			c.readers[domain][id] = true
+		}
+	}
+	generation := c.generations[domain]
+	c.mu.Unlock()
+
+	if ok {
+		tflog.Debug(ctx, "Using cached records", map[string]interface{}{"domain": domain})
+		return records, nil
+	}
+
+	// Callers after an invalidation must not join a retrieval that started before it
+	key := fmt.Sprintf("%s/%d", domain, generation)
+	results := c.group.DoChan(key, func() (interface{}, error) {
+		// The retrieval is shared, it must not fail for every caller when the one that started it is cancelled
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordRetrievalTimeout)
+		defer cancel()
+
+		records, err := client.RetrieveRecords(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.generations[domain] == generation {
+			c.records[domain] = records
+			c.readers[domain] = map[string]bool{}
+		}
+
+		return records, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		c.mu.Lock()
+		if c.generations[domain] == generation && id != "" {
+			c.readers[domain][id] = true
+		}
+		c.mu.Unlock()
+
+		return result.Val.([]porkbun.Record), nil
+	}
+}
+
+// Invalidate drops the cached records of the domain after one of them was created, updated or deleted
+func (c *recordCache) Invalidate(domain string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(domain)
+}
+
+func (c *recordCache) invalidate(domain string) {
+	delete(c.records, domain)
+	delete(c.readers, domain)
+	c.generations[domain]++
+}
+
+// InvalidateAll drops the cached records of every domain
+func (c *recordCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for domain := range c.records {
+		c.invalidate(domain)
+	}
+}
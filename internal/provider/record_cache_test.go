@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nrdcg/porkbun"
+)
+
+func Test_RecordCacheSharesRetrieval(t *testing.T) {
+	var retrievals atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		retrievals.Add(1)
+		// Give concurrent readers a chance to pile up on the same retrieval
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, `{"status":"SUCCESS","records":[{"id":"1","name":"providertest.top","type":"A","content":"0.0.0.1"}]}`)
+	}))
+	defer srv.Close()
+
+	client := porkbun.New("sk1_foobarbaz", "pk1_foobarbaz")
+	client.BaseURL, _ = url.Parse(srv.URL)
+	cache := newRecordCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			records, err := cache.Records(context.Background(), client, "providertest.top", id)
+			if err != nil || len(records) != 1 {
+				t.Errorf("unexpected result: %v, %v", records, err)
+			}
+		}(fmt.Sprint(i))
+	}
+	wg.Wait()
+
+	if _, err := cache.Records(context.Background(), client, "providertest.top", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := retrievals.Load(); got != 1 {
+		t.Errorf("expected a single retrieval, got %d", got)
+	}
+
+	cache.Invalidate("providertest.top")
+	if _, err := cache.Records(context.Background(), client, "providertest.top", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := retrievals.Load(); got != 2 {
+		t.Errorf("expected the invalidated domain to be retrieved again, got %d retrievals", got)
+	}
+}
+
+func Test_RecordCacheKeepsRecordsForOneRefresh(t *testing.T) {
+	var retrievals atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		retrievals.Add(1)
+		fmt.Fprint(w, `{"status":"SUCCESS","records":[]}`)
+	}))
+	defer srv.Close()
+
+	client := porkbun.New("sk1_foobarbaz", "pk1_foobarbaz")
+	client.BaseURL, _ = url.Parse(srv.URL)
+	cache := newRecordCache()
+
+	for _, id := range []string{"1", "2", "1", "2"} {
+		if _, err := cache.Records(context.Background(), client, "providertest.top", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := retrievals.Load(); got != 2 {
+		t.Errorf("expected a retrieval per refresh, got %d", got)
+	}
+}
+
+func Test_RecordCacheRetrievalOutlivesCancelledCaller(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprint(w, `{"status":"SUCCESS","records":[{"id":"1","name":"providertest.top","type":"A","content":"0.0.0.1"}]}`)
+	}))
+	defer srv.Close()
+
+	client := porkbun.New("sk1_foobarbaz", "pk1_foobarbaz")
+	client.BaseURL, _ = url.Parse(srv.URL)
+	cache := newRecordCache()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := cache.Records(ctx, client, "providertest.top", "1")
+		first <- err
+	}()
+
+	second := make(chan error)
+	go func() {
+		// Join the retrieval the first caller started
+		time.Sleep(20 * time.Millisecond)
+		records, err := cache.Records(context.Background(), client, "providertest.top", "2")
+		if err == nil && len(records) != 1 {
+			err = fmt.Errorf("unexpected records: %v", records)
+		}
+		second <- err
+	}()
+
+	time.Sleep(40 * time.Millisecond)
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("expected the cancelled caller to stop waiting, got %v", err)
+	}
+
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the shared retrieval to succeed, got %s", err)
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// dkimMinRsaBits is the smallest RSA key verifiers must accept, RFC 8301 section 3.2
+const dkimMinRsaBits = 1024
+
+// buildDkimRecord returns the content of the DKIM TXT record publishing the PEM encoded public key. RSA keys are
+// published as SubjectPublicKeyInfo like RFC 6376 and Ed25519 keys as the raw key like RFC 8463.
+func buildDkimRecord(publicKeyPem string) (string, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(publicKeyPem)))
+	if block == nil {
+		return "", fmt.Errorf("the public key must be PEM encoded")
+	}
+
+	var key any
+	var err error
+	switch {
+	case block.Type == "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case block.Type == "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case strings.Contains(block.Type, "PRIVATE KEY"):
+		return "", fmt.Errorf("expected a public key, got a %s, the tls provider exports the public key as public_key_pem", block.Type)
+	default:
+		return "", fmt.Errorf("expected a PUBLIC KEY, got a %s", block.Type)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if bits := key.N.BitLen(); bits < dkimMinRsaBits {
+			return "", fmt.Errorf("RSA keys for DKIM must have at least %d bits, got %d", dkimMinRsaBits, bits)
+		}
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return "", err
+		}
+		return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+	case ed25519.PublicKey:
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(key), nil
+	}
+
+	return "", fmt.Errorf("DKIM only supports RSA and Ed25519 keys, got %T", key)
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+const (
+	// dnskeyZoneKeyFlag marks keys that sign a zone, only those can be referred to by DS records
+	dnskeyZoneKeyFlag = 0x0100
+
+	// dnskeyProtocol is the only valid protocol of a DNSKEY record, RFC 4034 section 2.1.2
+	dnskeyProtocol = 3
+
+	dsDigestSha256 = 2
+	dsDigestSha384 = 4
+)
+
+// dnskey is the data of a DNSKEY record
+type dnskey struct {
+	flags     uint16
+	protocol  uint8
+	algorithm uint8
+	publicKey []byte
+}
+
+// parseDnskey reads a DNSKEY record in presentation format, either just its data like `257 3 13 base64...` or the
+// whole record like `example.com. 3600 IN DNSKEY 257 3 13 base64...`
+func parseDnskey(value string) (dnskey, error) {
+	var key dnskey
+
+	// Remove comments and the parentheses of records spanning several lines
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		line, _, _ = strings.Cut(line, ";")
+		lines = append(lines, line)
+	}
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(strings.Join(lines, " ")))
+
+	for i, field := range fields {
+		if strings.EqualFold(field, "DNSKEY") {
+			fields = fields[i+1:]
+			break
+		}
+	}
+
+	if len(fields) < 4 {
+		return key, fmt.Errorf("expected the flags, protocol, algorithm and public key of the DNSKEY record")
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return key, fmt.Errorf("invalid flags %q", fields[0])
+	}
+	protocol, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil || protocol != dnskeyProtocol {
+		return key, fmt.Errorf("the protocol must be %d, got %q", dnskeyProtocol, fields[1])
+	}
+	algorithm, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return key, fmt.Errorf("invalid algorithm %q", fields[2])
+	}
+	// The key tag of RSA/MD5 keys is computed differently, they are deprecated by RFC 6725
+	if algorithm == 1 {
+		return key, fmt.Errorf("the RSA/MD5 algorithm is not supported")
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(strings.Join(fields[3:], ""))
+	if err != nil || len(publicKey) == 0 {
+		return key, fmt.Errorf("the public key must be base64 encoded")
+	}
+
+	key = dnskey{flags: uint16(flags), protocol: uint8(protocol), algorithm: uint8(algorithm), publicKey: publicKey}
+	if key.flags&dnskeyZoneKeyFlag == 0 {
+		return key, fmt.Errorf("the key isn't a zone key, the flags must include %d", dnskeyZoneKeyFlag)
+	}
+
+	return key, nil
+}
+
+// rdata returns the data of the record in wire format
+func (k dnskey) rdata() []byte {
+	data := binary.BigEndian.AppendUint16(nil, k.flags)
+	data = append(data, k.protocol, k.algorithm)
+	return append(data, k.publicKey...)
+}
+
+// keyTag returns the key tag DS records use to refer to the key, RFC 4034 appendix B
+func (k dnskey) keyTag() uint16 {
+	var sum uint32
+	for i, b := range k.rdata() {
+		if i%2 == 0 {
+			sum += uint32(b) << 8
+		} else {
+			sum += uint32(b)
+		}
+	}
+	sum += sum >> 16
+
+	return uint16(sum)
+}
+
+// dsDigest returns the digest of a DS record for the key of the domain in uppercase hex, RFC 4034 section 5.1.4
+func (k dnskey) dsDigest(domain string, digestType uint8) (string, error) {
+	owner, err := normalizeDomainName(domain)
+	if err != nil {
+		return "", err
+	}
+	if owner == "" {
+		return "", fmt.Errorf("the domain must not be empty")
+	}
+
+	var h hash.Hash
+	switch digestType {
+	case dsDigestSha256:
+		h = sha256.New()
+	case dsDigestSha384:
+		h = sha512.New384()
+	default:
+		return "", fmt.Errorf("unsupported digest type %d", digestType)
+	}
+
+	// The owner name in canonical wire format, lowercase labels each prefixed with their length
+	for _, label := range strings.Split(owner, ".") {
+		h.Write([]byte{byte(len(label))})
+		h.Write([]byte(label))
+	}
+	h.Write([]byte{0})
+	h.Write(k.rdata())
+
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil))), nil
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_DnskeyDsDigest(t *testing.T) {
+	tests := []struct {
+		domain     string
+		dnskey     string
+		digestType uint8
+		keyTag     uint16
+		digest     string
+	}{
+		// RFC 4509 section 2.3
+		{
+			domain: "dskey.example.com",
+			dnskey: `dskey.example.com. 86400 IN DNSKEY 256 3 5 ( AQOeiiR0GOMYkDshWoSKz9Xz
+                                          fwJr1AYtsmx3TGkJaNXVbfi/
+                                          2pHm822aJ5iI9BMzNXxeYCmZ
+                                          DRD99WYwYqUSdjMmmAphXdvx
+                                          egXd/M5+X7OrzKBaMbCVdFLU
+                                          Uh6DhweJBjEVv5f2wwjM9Xzc
+                                          nOf+EPbtG9DMBmADjFDc2w/r
+                                          ljwvFw==
+                                          ) ;  key id = 60485`,
+			digestType: dsDigestSha256,
+			keyTag:     60485,
+			digest:     "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A",
+		},
+		// RFC 6605 section 6.1
+		{
+			domain:     "example.net",
+			dnskey:     "257 3 13 GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==",
+			digestType: dsDigestSha256,
+			keyTag:     55648,
+			digest:     "B4C8C1FE2E7477127B27115656AD6256F424625BF5C1E2770CE6D6E37DF61D17",
+		},
+		// RFC 6605 section 6.2
+		{
+			domain:     "Example.net.",
+			dnskey:     "257 3 14 xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40",
+			digestType: dsDigestSha384,
+			keyTag:     10771,
+			digest:     "72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A946DF983D6",
+		},
+	}
+
+	for _, tc := range tests {
+		key, err := parseDnskey(tc.dnskey)
+		if err != nil {
+			t.Errorf("%s: %s", tc.domain, err)
+			continue
+		}
+
+		if tag := key.keyTag(); tag != tc.keyTag {
+			t.Errorf("%s: expected key tag %d, got %d", tc.domain, tc.keyTag, tag)
+		}
+
+		digest, err := key.dsDigest(tc.domain, tc.digestType)
+		if err != nil || digest != tc.digest {
+			t.Errorf("%s: expected digest %s, got %s: %v", tc.domain, tc.digest, digest, err)
+		}
+	}
+}
+
+func Test_ParseDnskeyErrors(t *testing.T) {
+	key := "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="
+
+	for _, value := range []string{
+		"",
+		"257 3 13",
+		"257 2 13 " + key,
+		"257 3 13 not-base64!",
+		"1 3 13 " + key,
+		"257 3 1 " + key,
+		"flags 3 13 " + key,
+	} {
+		if _, err := parseDnskey(value); err == nil {
+			t.Errorf("expected %q to be an error", value)
+		}
+	}
+
+	if _, err := parseDnskey("257 3 13 " + strings.Replace(key, "GojI", "GojI ", 1)); err != nil {
+		t.Errorf("expected a key split by spaces to be valid: %s", err)
+	}
+}
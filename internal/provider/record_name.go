@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"golang.org/x/net/idna"
+)
+
+// domainNameProfile converts names to the lowercase ASCII form Porkbun returns them in. Underscores, as in
+// `_dmarc` or `_25._tcp`, and wildcards aren't valid host names but are valid record names.
+var domainNameProfile = idna.New(idna.MapForLookup(), idna.Transitional(false), idna.StrictDomainName(false))
+
+// normalizeDomainName returns the name in lowercase ASCII without a trailing dot, converting internationalized
+// labels to punycode
+func normalizeDomainName(name string) (string, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "", nil
+	}
+
+	ascii, err := domainNameProfile.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid name %q: %w", name, err)
+	}
+
+	labels := strings.Split(ascii, ".")
+	for i, label := range labels {
+		switch {
+		case label == "":
+			return "", fmt.Errorf("invalid name %q: empty label", name)
+		case strings.Contains(label, "*") && (label != "*" || i != 0):
+			return "", fmt.Errorf("invalid name %q: a wildcard must be the whole leftmost label", name)
+		}
+	}
+
+	return ascii, nil
+}
+
+// unicodeDomainName returns the name in lowercase without a trailing dot, converting punycode labels to Unicode
+func unicodeDomainName(name string) (string, error) {
+	ascii, err := normalizeDomainName(name)
+	if err != nil {
+		return "", err
+	}
+
+	unicode, err := domainNameProfile.ToUnicode(ascii)
+	if err != nil {
+		return "", fmt.Errorf("invalid name %q: %w", name, err)
+	}
+
+	return unicode, nil
+}
+
+// normalizeRecordName returns the name of a record relative to its domain in the form Porkbun uses, where `@` is
+// the apex like an empty name
+func normalizeRecordName(name string) (string, error) {
+	if strings.TrimSpace(name) == "@" {
+		return "", nil
+	}
+
+	return normalizeDomainName(name)
+}
+
+// recordNamesEquivalent reports whether two names refer to the same record, e.g. a Unicode name and its punycode
+func recordNamesEquivalent(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	normalizedA, errA := normalizeRecordName(a)
+	normalizedB, errB := normalizeRecordName(b)
+
+	return errA == nil && errB == nil && normalizedA == normalizedB
+}
+
+// requiresReplaceIfNameChanged requires replacing the resource when the configured name changes to one that isn't
+// equivalent, so switching between the Unicode and punycode forms of a name updates it in place
+func requiresReplaceIfNameChanged() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			if req.ConfigValue.IsNull() {
+				return
+			}
+			resp.RequiresReplace = !recordNamesEquivalent(req.StateValue.ValueString(), req.PlanValue.ValueString())
+		},
+		"If the value of this attribute changes to a different name, Terraform will destroy and recreate the resource.",
+		"If the value of this attribute changes to a different name, Terraform will destroy and recreate the resource.",
+	)
+}
+
+// recordFqdn returns the full name of a record from its name relative to the domain. An empty name or `@` is the
+// apex, and a name with a trailing dot is already fully qualified but must be within the domain.
+func recordFqdn(name string, domain string) (string, error) {
+	domain, err := normalizeDomainName(domain)
+	if err != nil {
+		return "", err
+	}
+	if domain == "" {
+		return "", fmt.Errorf("the domain must not be empty")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || name == "@" {
+		return domain, nil
+	}
+
+	if strings.HasSuffix(name, ".") {
+		fqdn, err := normalizeDomainName(name)
+		if err != nil {
+			return "", err
+		}
+
+		if fqdn != domain && !strings.HasSuffix(fqdn, "."+domain) {
+			return "", fmt.Errorf("%q is not within %q", name, domain)
+		}
+		return fqdn, nil
+	}
+
+	relative, err := normalizeDomainName(name)
+	if err != nil {
+		return "", err
+	}
+
+	return relative + "." + domain, nil
+}
+
+// relativeRecordName returns the name of a record relative to the domain from its full name, which is empty for the
+// apex. Only the domain at the end is removed, so `example.com.example.com` becomes `example.com`.
+func relativeRecordName(fqdn string, domain string) (string, error) {
+	domain, err := normalizeDomainName(domain)
+	if err != nil {
+		return "", err
+	}
+	if domain == "" {
+		return "", fmt.Errorf("the domain must not be empty")
+	}
+
+	normalized, err := normalizeDomainName(fqdn)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case normalized == domain:
+		return "", nil
+	case strings.HasSuffix(normalized, "."+domain):
+		return strings.TrimSuffix(normalized, "."+domain), nil
+	}
+
+	return "", fmt.Errorf("%q is not within %q", fqdn, domain)
+}
@@ -0,0 +1,113 @@
+package provider
+
+import "testing"
+
+func Test_RecordFqdn(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		expected string
+	}{
+		{"www", "example.com", "www.example.com"},
+		{"", "example.com", "example.com"},
+		{"@", "example.com.", "example.com"},
+		{"*", "example.com", "*.example.com"},
+		{"*.Dev", "Example.com", "*.dev.example.com"},
+		{"_25._tcp.mail", "example.com", "_25._tcp.mail.example.com"},
+		{"www.example.com.", "example.com", "www.example.com"},
+		{"example.com.", "example.com", "example.com"},
+		{"example.com", "example.com", "example.com.example.com"},
+		{"bücher", "münchen.de", "xn--bcher-kva.xn--mnchen-3ya.de"},
+	}
+
+	for _, tc := range tests {
+		actual, err := recordFqdn(tc.name, tc.domain)
+		if err != nil || actual != tc.expected {
+			t.Errorf("%q, %q: expected %q, got %q: %v", tc.name, tc.domain, tc.expected, actual, err)
+		}
+	}
+
+	for _, name := range []string{"www.example.org.", "www..dev", "a.*", "foo*", "-www"} {
+		if actual, err := recordFqdn(name, "example.com"); err == nil {
+			t.Errorf("%q: expected an error, got %q", name, actual)
+		}
+	}
+
+	if _, err := recordFqdn("www", ""); err == nil {
+		t.Error("expected an empty domain to be an error")
+	}
+}
+
+func Test_RelativeRecordName(t *testing.T) {
+	tests := []struct {
+		fqdn     string
+		domain   string
+		expected string
+	}{
+		{"www.example.com", "example.com", "www"},
+		{"example.com", "example.com", ""},
+		{"Example.com.", "example.com", ""},
+		{"*.dev.example.com", "example.com", "*.dev"},
+		{"example.com.example.com", "example.com", "example.com"},
+		{"xn--bcher-kva.xn--mnchen-3ya.de", "münchen.de", "xn--bcher-kva"},
+		{"bücher.münchen.de", "xn--mnchen-3ya.de", "xn--bcher-kva"},
+	}
+
+	for _, tc := range tests {
+		actual, err := relativeRecordName(tc.fqdn, tc.domain)
+		if err != nil || actual != tc.expected {
+			t.Errorf("%q, %q: expected %q, got %q: %v", tc.fqdn, tc.domain, tc.expected, actual, err)
+		}
+	}
+
+	for _, fqdn := range []string{"www.example.org", "notexample.com", "com"} {
+		if actual, err := relativeRecordName(fqdn, "example.com"); err == nil {
+			t.Errorf("%q: expected an error, got %q", fqdn, actual)
+		}
+	}
+}
+
+func Test_UnicodeDomainName(t *testing.T) {
+	tests := map[string]string{
+		"xn--bcher-kva.xn--mnchen-3ya.de": "bücher.münchen.de",
+		"Bücher.de.":                      "bücher.de",
+		"_dmarc.example.com":              "_dmarc.example.com",
+		"*.xn--mnchen-3ya.de":             "*.münchen.de",
+	}
+
+	for name, expected := range tests {
+		actual, err := unicodeDomainName(name)
+		if err != nil || actual != expected {
+			t.Errorf("%q: expected %q, got %q: %v", name, expected, actual, err)
+		}
+	}
+
+	if actual, err := unicodeDomainName("xn--zz"); err == nil {
+		t.Errorf("expected invalid punycode to be an error, got %q", actual)
+	}
+}
+
+func Test_RecordNamesEquivalent(t *testing.T) {
+	equivalent := [][2]string{
+		{"bücher", "xn--bcher-kva"},
+		{"WWW", "www"},
+		{"@", ""},
+		{"münchen.de", "xn--mnchen-3ya.de."},
+	}
+	for _, names := range equivalent {
+		if !recordNamesEquivalent(names[0], names[1]) {
+			t.Errorf("expected %q and %q to be equivalent", names[0], names[1])
+		}
+	}
+
+	different := [][2]string{
+		{"bücher", "bucher"},
+		{"www", ""},
+		{"www..dev", "www.dev"},
+	}
+	for _, names := range different {
+		if recordNamesEquivalent(names[0], names[1]) {
+			t.Errorf("expected %q and %q to be different", names[0], names[1])
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// spfVersion starts every SPF record
+	spfVersion = "v=spf1"
+
+	// spfMaxLookups is the number of DNS lookups after which receivers fail the SPF check, RFC 7208 section 4.6.4
+	spfMaxLookups = 10
+)
+
+// spfAllTerms are the valid values of the all mechanism that ends a record
+var spfAllTerms = []string{"-all", "~all", "?all", "+all"}
+
+// spfModifierName matches the name of a modifier like redirect= or exp=
+var spfModifierName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// buildSpfRecord builds the content of an SPF record from its mechanisms and modifiers, ending it with the all
+// mechanism unless all is empty
+func buildSpfRecord(terms []string, all string) (string, error) {
+	parts := []string{spfVersion}
+	modifiers := map[string]bool{}
+
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+
+		if _, err := validateSpfTerm(term); err != nil {
+			return "", err
+		}
+
+		if name, _, ok := spfModifier(term); ok {
+			name = strings.ToLower(name)
+			if modifiers[name] {
+				return "", fmt.Errorf("the %s modifier can only be used once", name)
+			}
+			modifiers[name] = true
+		}
+
+		parts = append(parts, term)
+	}
+
+	if all != "" {
+		valid := false
+		for _, term := range spfAllTerms {
+			valid = valid || all == term
+		}
+		if !valid {
+			return "", fmt.Errorf("all must be one of %s, got %q", strings.Join(spfAllTerms, ", "), all)
+		}
+
+		if modifiers["redirect"] {
+			return "", fmt.Errorf("the redirect modifier has no effect together with %s", all)
+		}
+
+		parts = append(parts, all)
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// validateSpfTerm checks the syntax of a mechanism or modifier and returns how many DNS lookups it causes
+func validateSpfTerm(term string) (int, error) {
+	if term == "" {
+		return 0, fmt.Errorf("SPF terms must not be empty")
+	}
+	if strings.ContainsAny(term, " \t") {
+		return 0, fmt.Errorf("invalid SPF term %q: terms must not contain spaces, pass each one separately", term)
+	}
+
+	if name, value, ok := spfModifier(term); ok {
+		switch strings.ToLower(name) {
+		case "redirect":
+			if value == "" {
+				return 0, fmt.Errorf("invalid SPF term %q: redirect needs a domain", term)
+			}
+			return 1, nil
+		case "exp":
+			if value == "" {
+				return 0, fmt.Errorf("invalid SPF term %q: exp needs a domain", term)
+			}
+		}
+		return 0, nil
+	}
+
+	mechanism := strings.TrimLeft(term, "+-~?")
+	if len(term)-len(mechanism) > 1 {
+		return 0, fmt.Errorf("invalid SPF term %q: only a single qualifier is allowed", term)
+	}
+
+	name, argument, _ := strings.Cut(mechanism, ":")
+	name, cidr, hasCidr := strings.Cut(name, "/")
+	if argument != "" {
+		// The CIDR length follows the domain, e.g. a:example.com/24
+		if i := strings.Index(argument, "/"); i >= 0 && !strings.EqualFold(name, "ip4") && !strings.EqualFold(name, "ip6") {
+			argument, cidr, hasCidr = argument[:i], argument[i+1:], true
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case "all":
+		return 0, fmt.Errorf("invalid SPF term %q: use the all argument to end the record", term)
+	case "include", "exists":
+		if argument == "" || hasCidr {
+			return 0, fmt.Errorf("invalid SPF term %q: %s needs a domain and no prefix length", term, name)
+		}
+		return 1, nil
+	case "a", "mx":
+		if strings.Contains(mechanism, ":") && argument == "" {
+			return 0, fmt.Errorf("invalid SPF term %q: the domain must not be empty", term)
+		}
+		if hasCidr {
+			if err := validateSpfDualCidr(cidr); err != nil {
+				return 0, fmt.Errorf("invalid SPF term %q: %w", term, err)
+			}
+		}
+		return 1, nil
+	case "ptr":
+		if hasCidr {
+			return 0, fmt.Errorf("invalid SPF term %q: ptr takes no prefix length", term)
+		}
+		return 1, nil
+	case "ip4", "ip6":
+		if hasCidr {
+			return 0, fmt.Errorf("invalid SPF term %q: the prefix length follows the address", term)
+		}
+		if err := validateSpfNetwork(name, argument); err != nil {
+			return 0, fmt.Errorf("invalid SPF term %q: %w", term, err)
+		}
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("invalid SPF term %q: unknown mechanism %q", term, name)
+}
+
+// spfModifier splits a modifier like redirect=_spf.example.com into its name and value
+func spfModifier(term string) (string, string, bool) {
+	name, value, ok := strings.Cut(term, "=")
+	if !ok || !spfModifierName.MatchString(name) {
+		return "", "", false
+	}
+
+	return name, value, true
+}
+
+// validateSpfDualCidr checks the prefix lengths of an a or mx mechanism, e.g. 24, /64 or 24//64
+func validateSpfDualCidr(cidr string) error {
+	ip4, ip6, dual := strings.Cut(cidr, "//")
+	if strings.HasPrefix(cidr, "/") {
+		ip4, ip6, dual = "", cidr[1:], true
+	}
+
+	if ip4 != "" {
+		if length, err := strconv.Atoi(ip4); err != nil || length < 0 || length > 32 {
+			return fmt.Errorf("invalid IPv4 prefix length %q", ip4)
+		}
+	}
+
+	if dual {
+		if length, err := strconv.Atoi(ip6); err != nil || length < 0 || length > 128 {
+			return fmt.Errorf("invalid IPv6 prefix length %q", ip6)
+		}
+	}
+
+	return nil
+}
+
+// validateSpfNetwork checks the address or network of an ip4 or ip6 mechanism
+func validateSpfNetwork(mechanism string, network string) error {
+	var addr netip.Addr
+	var err error
+	if strings.Contains(network, "/") {
+		var prefix netip.Prefix
+		prefix, err = netip.ParsePrefix(network)
+		addr = prefix.Addr()
+	} else {
+		addr, err = netip.ParseAddr(network)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid network %q", network)
+	}
+
+	switch {
+	case strings.EqualFold(mechanism, "ip4") && !addr.Is4():
+		return fmt.Errorf("%q is not an IPv4 network", network)
+	case strings.EqualFold(mechanism, "ip6") && !addr.Is6():
+		return fmt.Errorf("%q is not an IPv6 network", network)
+	}
+
+	return nil
+}
+
+// countSpfLookups returns the number of DNS lookups the terms of an SPF record cause, not counting those of the
+// records it includes. It reports false when the TXT value isn't an SPF record.
+func countSpfLookups(value string) (int, bool) {
+	terms := strings.Fields(parseTxtContent(value))
+	if len(terms) == 0 || !strings.EqualFold(terms[0], spfVersion) {
+		return 0, false
+	}
+
+	lookups := 0
+	for _, term := range terms[1:] {
+		// Invalid terms are left to the receivers, the count is only used to warn
+		count, _ := validateSpfTerm(term)
+		lookups += count
+	}
+
+	return lookups, true
+}
@@ -0,0 +1,66 @@
+package provider
+
+import "testing"
+
+func Test_BuildSpfRecord(t *testing.T) {
+	tests := []struct {
+		terms    []string
+		all      string
+		expected string
+	}{
+		{[]string{"mx", "include:_spf.google.com", "ip4:192.0.2.0/24", "ip6:2001:db8::/32"}, "-all", "v=spf1 mx include:_spf.google.com ip4:192.0.2.0/24 ip6:2001:db8::/32 -all"},
+		{[]string{"a:mail.example.com/24", "mx//64", "~a/24//64", "exists:%{i}.spf.example.com"}, "~all", "v=spf1 a:mail.example.com/24 mx//64 ~a/24//64 exists:%{i}.spf.example.com ~all"},
+		{[]string{"redirect=_spf.example.com"}, "", "v=spf1 redirect=_spf.example.com"},
+		{[]string{}, "-all", "v=spf1 -all"},
+	}
+
+	for _, tc := range tests {
+		actual, err := buildSpfRecord(tc.terms, tc.all)
+		if err != nil || actual != tc.expected {
+			t.Errorf("%v: expected %q, got %q: %v", tc.terms, tc.expected, actual, err)
+		}
+	}
+
+	invalid := []struct {
+		terms []string
+		all   string
+	}{
+		{[]string{"include"}, "-all"},
+		{[]string{"ip4:2001:db8::1"}, "-all"},
+		{[]string{"ip6:192.0.2.1"}, "-all"},
+		{[]string{"ip4:192.0.2.0/33"}, "-all"},
+		{[]string{"a/33"}, "-all"},
+		{[]string{"mx include:example.com"}, "-all"},
+		{[]string{"-all"}, ""},
+		{[]string{"--mx"}, "-all"},
+		{[]string{"spf:example.com"}, "-all"},
+		{[]string{"redirect=a.example.com", "redirect=b.example.com"}, ""},
+		{[]string{"redirect=_spf.example.com"}, "-all"},
+		{[]string{"mx"}, "all"},
+	}
+
+	for _, tc := range invalid {
+		if actual, err := buildSpfRecord(tc.terms, tc.all); err == nil {
+			t.Errorf("%v %q: expected an error, got %q", tc.terms, tc.all, actual)
+		}
+	}
+}
+
+func Test_CountSpfLookups(t *testing.T) {
+	tests := map[string]int{
+		"v=spf1 -all": 0,
+		"v=spf1 a mx ptr include:a.example.com exists:b.example.com ip4:192.0.2.1 redirect=c.example.com": 6,
+		`"v=spf1 include:a.example.com " "include:b.example.com -all"`:                                    2,
+	}
+
+	for content, expected := range tests {
+		actual, ok := countSpfLookups(content)
+		if !ok || actual != expected {
+			t.Errorf("%s: expected %d lookups, got %d", content, expected, actual)
+		}
+	}
+
+	if _, ok := countSpfLookups("google-site-verification=abc"); ok {
+		t.Error("expected a TXT value without v=spf1 not to be an SPF record")
+	}
+}
@@ -0,0 +1,523 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// SvcParamKeys as registered in RFC 9460 section 14.3.2, in their canonical order
+const (
+	svcParamMandatory     = 0
+	svcParamAlpn          = 1
+	svcParamNoDefaultAlpn = 2
+	svcParamPort          = 3
+	svcParamIpv4Hint      = 4
+	svcParamEch           = 5
+	svcParamIpv6Hint      = 6
+)
+
+var svcParamNames = map[int]string{
+	svcParamMandatory:     "mandatory",
+	svcParamAlpn:          "alpn",
+	svcParamNoDefaultAlpn: "no-default-alpn",
+	svcParamPort:          "port",
+	svcParamIpv4Hint:      "ipv4hint",
+	svcParamEch:           "ech",
+	svcParamIpv6Hint:      "ipv6hint",
+}
+
+// porkbunSvcbModel describes the structured form of an HTTPS or SVCB record
+type porkbunSvcbModel struct {
+	Priority      types.Int64  `tfsdk:"priority"`
+	Target        types.String `tfsdk:"target"`
+	Alpn          types.List   `tfsdk:"alpn"`
+	NoDefaultAlpn types.Bool   `tfsdk:"no_default_alpn"`
+	Port          types.Int64  `tfsdk:"port"`
+	Ipv4Hint      types.List   `tfsdk:"ipv4hint"`
+	Ipv6Hint      types.List   `tfsdk:"ipv6hint"`
+	Ech           types.String `tfsdk:"ech"`
+}
+
+// svcbContent is the plain representation of an HTTPS or SVCB record used to render and parse content
+type svcbContent struct {
+	priority      int64
+	target        string
+	alpn          []string
+	noDefaultAlpn bool
+	port          *int64
+	ipv4Hint      []string
+	ipv6Hint      []string
+	ech           string
+}
+
+func svcbBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Structured content for an `HTTPS` or `SVCB` record. When set, `content` is computed from this block and must not be set",
+		Attributes: map[string]schema.Attribute{
+			"priority": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The SvcPriority of the record, `0` puts the record in AliasMode",
+			},
+			"target": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The TargetName of the record, `.` refers to the name of the record itself",
+			},
+			"alpn": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The supported ALPN protocol identifiers in order of preference, e.g. `[\"h3\", \"h2\"]`",
+			},
+			"no_default_alpn": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the default ALPN protocol of the scheme is unsupported, requires `alpn` to be set",
+				Default:             booldefault.StaticBool(false),
+			},
+			"port": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The alternative port of the service",
+			},
+			"ipv4hint": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "IPv4 addresses clients may use to reach the service",
+			},
+			"ipv6hint": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "IPv6 addresses clients may use to reach the service",
+			},
+			"ech": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The base64 encoded ECHConfigList of the service",
+			},
+		},
+	}
+}
+
+// isKnown reports whether every attribute of the block is known so the content can be rendered
+func (m *porkbunSvcbModel) isKnown() bool {
+	for _, v := range []attr.Value{m.Priority, m.Target, m.Alpn, m.NoDefaultAlpn, m.Port, m.Ipv4Hint, m.Ipv6Hint, m.Ech} {
+		if v.IsUnknown() {
+			return false
+		}
+	}
+
+	for _, l := range []types.List{m.Alpn, m.Ipv4Hint, m.Ipv6Hint} {
+		for _, element := range l.Elements() {
+			if element.IsUnknown() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// validate checks the block, reporting problems against the attribute at p
+func (m *porkbunSvcbModel) validate(ctx context.Context, p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Attributes of a single nested block cannot be marked as required, so check them here
+	if m.Priority.IsNull() {
+		diags.AddAttributeError(p.AtName("priority"), "Missing SVCB priority", "priority must be set in the svcb block")
+	}
+
+	if m.Target.IsNull() {
+		diags.AddAttributeError(p.AtName("target"), "Missing SVCB target", "target must be set in the svcb block")
+	}
+
+	if diags.HasError() || !m.isKnown() {
+		return diags
+	}
+
+	content, d := m.toContent(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if content.priority < 0 || content.priority > 65535 {
+		diags.AddAttributeError(p.AtName("priority"), "invalid value for priority", fmt.Sprintf("priority must be between 0 and 65535, got %d", content.priority))
+	}
+
+	if content.target != "." {
+		if err := validateDomainName(content.target); err != nil {
+			diags.AddAttributeError(p.AtName("target"), "invalid value for target", err.Error())
+		}
+	}
+
+	if content.priority == 0 && content.hasParams() {
+		diags.AddAttributeError(p, "Invalid SVCB record", "records with priority 0 are in AliasMode and cannot have any parameters")
+	}
+
+	if content.noDefaultAlpn && len(content.alpn) == 0 {
+		diags.AddAttributeError(p.AtName("no_default_alpn"), "Invalid SVCB record", "no_default_alpn requires alpn to be set")
+	}
+
+	if !m.Alpn.IsNull() && len(content.alpn) == 0 {
+		diags.AddAttributeError(p.AtName("alpn"), "invalid value for alpn", "alpn must contain at least one protocol identifier")
+	}
+
+	for _, id := range content.alpn {
+		if id == "" || len(id) > 255 {
+			diags.AddAttributeError(p.AtName("alpn"), "invalid value for alpn", fmt.Sprintf("protocol identifier %q must be between 1 and 255 characters", id))
+		}
+	}
+
+	if content.port != nil && (*content.port < 0 || *content.port > 65535) {
+		diags.AddAttributeError(p.AtName("port"), "invalid value for port", fmt.Sprintf("port must be between 0 and 65535, got %d", *content.port))
+	}
+
+	if !m.Ipv4Hint.IsNull() && len(content.ipv4Hint) == 0 {
+		diags.AddAttributeError(p.AtName("ipv4hint"), "invalid value for ipv4hint", "ipv4hint must contain at least one address")
+	}
+
+	for _, ip := range content.ipv4Hint {
+		if addr, err := netip.ParseAddr(ip); err != nil || !addr.Is4() {
+			diags.AddAttributeError(p.AtName("ipv4hint"), "invalid value for ipv4hint", fmt.Sprintf("%q is not an IPv4 address", ip))
+		}
+	}
+
+	if !m.Ipv6Hint.IsNull() && len(content.ipv6Hint) == 0 {
+		diags.AddAttributeError(p.AtName("ipv6hint"), "invalid value for ipv6hint", "ipv6hint must contain at least one address")
+	}
+
+	for _, ip := range content.ipv6Hint {
+		if addr, err := netip.ParseAddr(ip); err != nil || !addr.Is6() || addr.Is4In6() {
+			diags.AddAttributeError(p.AtName("ipv6hint"), "invalid value for ipv6hint", fmt.Sprintf("%q is not an IPv6 address", ip))
+		}
+	}
+
+	if !m.Ech.IsNull() {
+		if _, err := base64.StdEncoding.DecodeString(content.ech); err != nil || content.ech == "" {
+			diags.AddAttributeError(p.AtName("ech"), "invalid value for ech", "ech must be a base64 encoded ECHConfigList")
+		}
+	}
+
+	return diags
+}
+
+// content renders the block in the canonical presentation format
+func (m *porkbunSvcbModel) content(ctx context.Context) (string, diag.Diagnostics) {
+	content, diags := m.toContent(ctx)
+	return content.String(), diags
+}
+
+func (m *porkbunSvcbModel) toContent(ctx context.Context) (svcbContent, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	content := svcbContent{
+		priority:      m.Priority.ValueInt64(),
+		target:        m.Target.ValueString(),
+		noDefaultAlpn: m.NoDefaultAlpn.ValueBool(),
+		ech:           m.Ech.ValueString(),
+	}
+
+	if !m.Port.IsNull() {
+		port := m.Port.ValueInt64()
+		content.port = &port
+	}
+
+	diags.Append(m.Alpn.ElementsAs(ctx, &content.alpn, false)...)
+	diags.Append(m.Ipv4Hint.ElementsAs(ctx, &content.ipv4Hint, false)...)
+	diags.Append(m.Ipv6Hint.ElementsAs(ctx, &content.ipv6Hint, false)...)
+
+	return content, diags
+}
+
+func (c svcbContent) toModel() *porkbunSvcbModel {
+	m := &porkbunSvcbModel{
+		Priority:      types.Int64Value(c.priority),
+		Target:        types.StringValue(c.target),
+		Alpn:          stringListOrNull(c.alpn),
+		NoDefaultAlpn: types.BoolValue(c.noDefaultAlpn),
+		Port:          types.Int64Null(),
+		Ipv4Hint:      stringListOrNull(c.ipv4Hint),
+		Ipv6Hint:      stringListOrNull(c.ipv6Hint),
+		Ech:           types.StringNull(),
+	}
+
+	if c.port != nil {
+		m.Port = types.Int64Value(*c.port)
+	}
+
+	if c.ech != "" {
+		m.Ech = types.StringValue(c.ech)
+	}
+
+	return m
+}
+
+func stringListOrNull(values []string) types.List {
+	if len(values) == 0 {
+		return types.ListNull(types.StringType)
+	}
+
+	elements := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, types.StringValue(v))
+	}
+
+	return types.ListValueMust(types.StringType, elements)
+}
+
+func (c svcbContent) hasParams() bool {
+	return len(c.alpn) > 0 || c.noDefaultAlpn || c.port != nil || len(c.ipv4Hint) > 0 || len(c.ipv6Hint) > 0 || c.ech != ""
+}
+
+// String renders the record in presentation format with the parameters sorted by their key
+func (c svcbContent) String() string {
+	target := c.target
+	if target != "." && !strings.HasSuffix(target, ".") {
+		target += "."
+	}
+
+	fields := []string{strconv.FormatInt(c.priority, 10), target}
+
+	if len(c.alpn) > 0 {
+		escaped := make([]string, 0, len(c.alpn))
+		for _, id := range c.alpn {
+			id = strings.ReplaceAll(id, `\`, `\\`)
+			escaped = append(escaped, strings.ReplaceAll(id, ",", `\,`))
+		}
+		fields = append(fields, svcParamNames[svcParamAlpn]+"="+quoteSvcParamValue(strings.Join(escaped, ",")))
+	}
+
+	if c.noDefaultAlpn {
+		fields = append(fields, svcParamNames[svcParamNoDefaultAlpn])
+	}
+
+	if c.port != nil {
+		fields = append(fields, fmt.Sprintf("%s=%d", svcParamNames[svcParamPort], *c.port))
+	}
+
+	if len(c.ipv4Hint) > 0 {
+		fields = append(fields, svcParamNames[svcParamIpv4Hint]+"="+strings.Join(c.ipv4Hint, ","))
+	}
+
+	if c.ech != "" {
+		fields = append(fields, svcParamNames[svcParamEch]+"="+c.ech)
+	}
+
+	if len(c.ipv6Hint) > 0 {
+		hints := make([]string, 0, len(c.ipv6Hint))
+		for _, ip := range c.ipv6Hint {
+			// Render addresses in their compressed form so they compare equal to what is read back
+			if addr, err := netip.ParseAddr(ip); err == nil {
+				ip = addr.String()
+			}
+			hints = append(hints, ip)
+		}
+		fields = append(fields, svcParamNames[svcParamIpv6Hint]+"="+strings.Join(hints, ","))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// quoteSvcParamValue escapes a value for presentation format, quoting it when it contains whitespace or quotes
+func quoteSvcParamValue(value string) string {
+	quote := strings.ContainsAny(value, " \t\";")
+
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	if quote {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// parseSvcbContent parses HTTPS or SVCB content in presentation format. Parameters may be in any order,
+// quoted or unquoted and use either their name or the generic `keyNNNNN` form.
+func parseSvcbContent(content string) (svcbContent, error) {
+	var c svcbContent
+
+	tokens, err := splitPresentationFields(content)
+	if err != nil {
+		return c, err
+	}
+
+	if len(tokens) < 2 {
+		return c, fmt.Errorf("expected `<priority> <target> [params...]`, got %q", content)
+	}
+
+	c.priority, err = strconv.ParseInt(tokens[0], 10, 64)
+	if err != nil {
+		return c, fmt.Errorf("invalid priority in %q: %s", content, err)
+	}
+
+	c.target = tokens[1]
+	if c.target != "." {
+		c.target = strings.TrimSuffix(c.target, ".")
+	}
+
+	seen := map[int]bool{}
+	for _, param := range tokens[2:] {
+		key, value, hasValue := strings.Cut(param, "=")
+
+		number, err := svcParamKeyNumber(key)
+		if err != nil {
+			return c, err
+		}
+
+		if seen[number] {
+			return c, fmt.Errorf("duplicate SvcParam %q in %q", key, content)
+		}
+		seen[number] = true
+
+		if !hasValue && number != svcParamNoDefaultAlpn {
+			return c, fmt.Errorf("SvcParam %q in %q requires a value", key, content)
+		}
+
+		switch number {
+		case svcParamAlpn:
+			c.alpn = splitSvcParamList(value)
+		case svcParamNoDefaultAlpn:
+			c.noDefaultAlpn = true
+		case svcParamPort:
+			port, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return c, fmt.Errorf("invalid port in %q: %s", content, err)
+			}
+			c.port = &port
+		case svcParamIpv4Hint:
+			c.ipv4Hint = strings.Split(value, ",")
+		case svcParamEch:
+			c.ech = value
+		case svcParamIpv6Hint:
+			for _, ip := range strings.Split(value, ",") {
+				if addr, err := netip.ParseAddr(ip); err == nil {
+					ip = addr.String()
+				}
+				c.ipv6Hint = append(c.ipv6Hint, ip)
+			}
+		default:
+			return c, fmt.Errorf("unsupported SvcParam %q in %q, use content to manage this record instead", key, content)
+		}
+	}
+
+	return c, nil
+}
+
+func svcParamKeyNumber(key string) (int, error) {
+	key = strings.ToLower(key)
+
+	for number, name := range svcParamNames {
+		if name == key {
+			return number, nil
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(key, "key"); ok {
+		number, err := strconv.Atoi(rest)
+		if err == nil && number >= 0 && number <= 65535 {
+			return number, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown SvcParam key %q", key)
+}
+
+// splitPresentationFields splits content on whitespace, keeping quoted strings together and removing the quotes.
+// Escapes are decoded, so a value list is left with a single level of escaping for splitSvcParamList.
+func splitPresentationFields(content string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inQuotes, inField := false, false
+
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case ch == '\\' && i+3 < len(content) && isDecimalEscape(content[i+1:i+4]):
+			n, _ := strconv.Atoi(content[i+1 : i+4])
+			current.WriteByte(byte(n))
+			i += 3
+			inField = true
+		case ch == '\\' && i+1 < len(content):
+			current.WriteByte(content[i+1])
+			i++
+			inField = true
+		case ch == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case (ch == ' ' || ch == '\t') && !inQuotes:
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteByte(ch)
+			inField = true
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quoted string in %q", content)
+	}
+
+	if inField {
+		fields = append(fields, current.String())
+	}
+
+	return fields, nil
+}
+
+// isDecimalEscape reports whether digits is the DDD part of a `\DDD` escape
+func isDecimalEscape(digits string) bool {
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+
+	n, _ := strconv.Atoi(digits)
+	return n <= 255
+}
+
+// splitSvcParamList splits a comma separated value list, honouring escaped commas
+func splitSvcParamList(value string) []string {
+	var values []string
+	var current strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			i++
+			current.WriteByte(value[i])
+		case value[i] == ',':
+			values = append(values, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+
+	return append(values, current.String())
+}
+
+// svcbContentEqual reports whether two HTTPS or SVCB contents describe the same record
+func svcbContentEqual(a string, b string) bool {
+	ca, err := parseSvcbContent(a)
+	if err != nil {
+		return false
+	}
+
+	cb, err := parseSvcbContent(b)
+	if err != nil {
+		return false
+	}
+
+	return ca.String() == cb.String()
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ParseSvcbContent(t *testing.T) {
+	tests := map[string]string{
+		`1 . alpn=h3,h2`:                                            `1 . alpn=h3,h2`,
+		`1 . port="8443" alpn="h3,h2"`:                              `1 . alpn=h3,h2 port=8443`,
+		`1 svc.example.com. ipv6hint=2001:db8:0::1 key3=443`:        `1 svc.example.com. port=443 ipv6hint=2001:db8::1`,
+		`16 svc.example.com ech=AEX+DQBB ipv4hint=192.0.2.1`:        `16 svc.example.com. ipv4hint=192.0.2.1 ech=AEX+DQBB`,
+		`1 . no-default-alpn alpn=h2`:                               `1 . alpn=h2 no-default-alpn`,
+		`1 . alpn=f\\,oo,bar`:                                       `1 . alpn=f\\,oo,bar`,
+		`1 . alpn="f\\\\oo\\,bar,h2"`:                               `1 . alpn=f\\\\oo\\,bar,h2`,
+		`1 . alpn=f\\\092oo\092,bar,h2`:                             `1 . alpn=f\\\\oo\\,bar,h2`,
+		`0 pool.svc.example.com.`:                                   `0 pool.svc.example.com.`,
+		`2 .   ipv4hint=192.0.2.1,192.0.2.2   ipv6hint=2001:db8::1`: `2 . ipv4hint=192.0.2.1,192.0.2.2 ipv6hint=2001:db8::1`,
+	}
+
+	for content, expected := range tests {
+		svcb, err := parseSvcbContent(content)
+		if err != nil {
+			t.Errorf("parsing %q: %s", content, err)
+			continue
+		}
+
+		if got := svcb.String(); got != expected {
+			t.Errorf("parsing %q: expected %q, got %q", content, expected, got)
+		}
+	}
+
+	for _, content := range []string{"", "1", `1 . alpn="h2`, "1 . port=https", "1 . alpn=h2 alpn=h3", "1 . mandatory=alpn", "1 . key65000=foo"} {
+		if _, err := parseSvcbContent(content); err == nil {
+			t.Errorf("expected an error parsing %q", content)
+		}
+	}
+}
+
+func Test_SvcbContentRoundTrip(t *testing.T) {
+	for _, alpn := range [][]string{{`f\oo,bar`, "h2"}, {`h"2`, "h3 draft"}, {`\`, `\"`}} {
+		content := svcbContent{priority: 1, target: ".", alpn: alpn}.String()
+
+		parsed, err := parseSvcbContent(content)
+		if err != nil {
+			t.Errorf("parsing %q: %s", content, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(parsed.alpn, alpn) {
+			t.Errorf("expected %q to parse back to %q, got %q", content, alpn, parsed.alpn)
+		}
+	}
+}
+
+func Test_SvcbContentEqual(t *testing.T) {
+	if !svcbContentEqual(`1 . alpn="h3,h2" port=443`, `1 . port=443 alpn=h3,h2`) {
+		t.Error("expected parameters in a different order to be equal")
+	}
+
+	if svcbContentEqual(`1 . alpn=h3,h2`, `1 . alpn=h2,h3`) {
+		t.Error("expected alpn in a different order to differ")
+	}
+}
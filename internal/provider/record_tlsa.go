@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Selectors and matching types as defined in RFC 6698 section 2.1
+const (
+	tlsaSelectorCertificate = 0
+	tlsaSelectorSpki        = 1
+
+	tlsaMatchingFull   = 0
+	tlsaMatchingSha256 = 1
+	tlsaMatchingSha512 = 2
+)
+
+// porkbunTlsaModel describes the structured form of a TLSA record
+type porkbunTlsaModel struct {
+	Usage          types.Int64  `tfsdk:"usage"`
+	Selector       types.Int64  `tfsdk:"selector"`
+	MatchingType   types.Int64  `tfsdk:"matching_type"`
+	Data           types.String `tfsdk:"data"`
+	CertificatePem types.String `tfsdk:"certificate_pem"`
+}
+
+func tlsaBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Structured content for a `TLSA` record. When set, `content` is computed from this block and must not be set",
+		Attributes: map[string]schema.Attribute{
+			"usage": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The certificate usage, `0` (PKIX-TA), `1` (PKIX-EE), `2` (DANE-TA) or `3` (DANE-EE)",
+			},
+			"selector": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Which part of the certificate is matched, `0` for the full certificate or `1` for the SubjectPublicKeyInfo",
+			},
+			"matching_type": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "How the data is presented, `0` for the exact match, `1` for a SHA-256 or `2` for a SHA-512 digest",
+			},
+			"data": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The hex encoded certificate association data. Computed when `certificate_pem` is set",
+			},
+			"certificate_pem": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A PEM encoded certificate to compute `data` from for the given `selector` and `matching_type`",
+			},
+		},
+	}
+}
+
+// isKnown reports whether every attribute needed to render the block is known
+func (m *porkbunTlsaModel) isKnown() bool {
+	if m.Usage.IsUnknown() || m.Selector.IsUnknown() || m.MatchingType.IsUnknown() || m.CertificatePem.IsUnknown() {
+		return false
+	}
+
+	return !m.CertificatePem.IsNull() || !m.Data.IsUnknown()
+}
+
+// validate checks the block, reporting problems against the attribute at p
+func (m *porkbunTlsaModel) validate(p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Attributes of a single nested block cannot be marked as required, so check them here
+	for name, value := range map[string]types.Int64{"usage": m.Usage, "selector": m.Selector, "matching_type": m.MatchingType} {
+		if value.IsNull() {
+			diags.AddAttributeError(p.AtName(name), "Missing TLSA "+name, name+" must be set in the tlsa block")
+		}
+	}
+
+	if m.Data.IsNull() == m.CertificatePem.IsNull() {
+		diags.AddAttributeError(p, "Invalid TLSA record", "exactly one of data or certificate_pem must be set in the tlsa block")
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if !m.Usage.IsUnknown() && (m.Usage.ValueInt64() < 0 || m.Usage.ValueInt64() > 3) {
+		diags.AddAttributeError(p.AtName("usage"), "invalid value for usage", fmt.Sprintf("usage must be between 0 and 3, got %d", m.Usage.ValueInt64()))
+	}
+
+	if !m.Selector.IsUnknown() && (m.Selector.ValueInt64() < tlsaSelectorCertificate || m.Selector.ValueInt64() > tlsaSelectorSpki) {
+		diags.AddAttributeError(p.AtName("selector"), "invalid value for selector", fmt.Sprintf("selector must be 0 or 1, got %d", m.Selector.ValueInt64()))
+	}
+
+	if !m.MatchingType.IsUnknown() && (m.MatchingType.ValueInt64() < tlsaMatchingFull || m.MatchingType.ValueInt64() > tlsaMatchingSha512) {
+		diags.AddAttributeError(p.AtName("matching_type"), "invalid value for matching_type", fmt.Sprintf("matching_type must be between 0 and 2, got %d", m.MatchingType.ValueInt64()))
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if !m.Data.IsNull() && !m.Data.IsUnknown() && !m.MatchingType.IsUnknown() {
+		if err := validateTlsaData(m.MatchingType.ValueInt64(), m.Data.ValueString()); err != nil {
+			diags.AddAttributeError(p.AtName("data"), "invalid value for data", err.Error())
+		}
+	}
+
+	if !m.CertificatePem.IsNull() && !m.CertificatePem.IsUnknown() {
+		if _, err := parseCertificatePem(m.CertificatePem.ValueString()); err != nil {
+			diags.AddAttributeError(p.AtName("certificate_pem"), "invalid value for certificate_pem", err.Error())
+		}
+	}
+
+	return diags
+}
+
+// computeData fills in data from certificate_pem when it is set
+func (m *porkbunTlsaModel) computeData() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m.CertificatePem.IsNull() {
+		return diags
+	}
+
+	data, err := tlsaAssociationData(m.CertificatePem.ValueString(), m.Selector.ValueInt64(), m.MatchingType.ValueInt64())
+	if err != nil {
+		diags.AddAttributeError(path.Root("tlsa").AtName("certificate_pem"), "Could not compute TLSA data", err.Error())
+		return diags
+	}
+
+	m.Data = types.StringValue(data)
+
+	return diags
+}
+
+// content renders the block in presentation format, e.g. `3 1 1 <hex>`
+func (m *porkbunTlsaModel) content() string {
+	return formatTlsaContent(m.Usage.ValueInt64(), m.Selector.ValueInt64(), m.MatchingType.ValueInt64(), m.Data.ValueString())
+}
+
+func formatTlsaContent(usage int64, selector int64, matchingType int64, data string) string {
+	return fmt.Sprintf("%d %d %d %s", usage, selector, matchingType, normalizeHex(data))
+}
+
+// parseTlsaContent parses TLSA content as returned by Porkbun, the data may be split by whitespace
+func parseTlsaContent(content string) (*porkbunTlsaModel, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("expected `<usage> <selector> <matching type> <data>`, got %q", content)
+	}
+
+	var numbers [3]int64
+	for i := range numbers {
+		number, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLSA record %q: %s", content, err)
+		}
+		numbers[i] = number
+	}
+
+	return &porkbunTlsaModel{
+		Usage:          types.Int64Value(numbers[0]),
+		Selector:       types.Int64Value(numbers[1]),
+		MatchingType:   types.Int64Value(numbers[2]),
+		Data:           types.StringValue(normalizeHex(strings.Join(fields[3:], ""))),
+		CertificatePem: types.StringNull(),
+	}, nil
+}
+
+// tlsaContentEqual reports whether two TLSA contents only differ in case and whitespace
+func tlsaContentEqual(a string, b string) bool {
+	ta, err := parseTlsaContent(a)
+	if err != nil {
+		return false
+	}
+
+	tb, err := parseTlsaContent(b)
+	if err != nil {
+		return false
+	}
+
+	return ta.content() == tb.content()
+}
+
+func normalizeHex(data string) string {
+	return strings.ToLower(strings.Join(strings.Fields(data), ""))
+}
+
+func validateTlsaData(matchingType int64, data string) error {
+	data = normalizeHex(data)
+
+	if _, err := hex.DecodeString(data); err != nil {
+		return fmt.Errorf("data must be hex encoded: %s", err)
+	}
+
+	switch matchingType {
+	case tlsaMatchingSha256:
+		if len(data) != sha256.Size*2 {
+			return fmt.Errorf("a SHA-256 digest must be %d hex characters, got %d", sha256.Size*2, len(data))
+		}
+	case tlsaMatchingSha512:
+		if len(data) != sha512.Size*2 {
+			return fmt.Errorf("a SHA-512 digest must be %d hex characters, got %d", sha512.Size*2, len(data))
+		}
+	default:
+		if len(data) == 0 {
+			return fmt.Errorf("data cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+func parseCertificatePem(certificatePem string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificatePem))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("expected a PEM encoded CERTIFICATE block")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// tlsaAssociationData computes the hex encoded certificate association data of a PEM encoded certificate
+func tlsaAssociationData(certificatePem string, selector int64, matchingType int64) (string, error) {
+	certificate, err := parseCertificatePem(certificatePem)
+	if err != nil {
+		return "", err
+	}
+
+	var selected []byte
+	switch selector {
+	case tlsaSelectorCertificate:
+		selected = certificate.Raw
+	case tlsaSelectorSpki:
+		selected = certificate.RawSubjectPublicKeyInfo
+	default:
+		return "", fmt.Errorf("unsupported selector %d", selector)
+	}
+
+	switch matchingType {
+	case tlsaMatchingFull:
+		return hex.EncodeToString(selected), nil
+	case tlsaMatchingSha256:
+		digest := sha256.Sum256(selected)
+		return hex.EncodeToString(digest[:]), nil
+	case tlsaMatchingSha512:
+		digest := sha512.Sum512(selected)
+		return hex.EncodeToString(digest[:]), nil
+	default:
+		return "", fmt.Errorf("unsupported matching type %d", matchingType)
+	}
+}
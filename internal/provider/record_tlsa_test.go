@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCertificatePem(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mail.providertest.top"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func Test_TlsaAssociationData(t *testing.T) {
+	certificatePem := testCertificatePem(t)
+	certificate, err := parseCertificatePem(certificatePem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spkiSha256 := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+	certSha512 := sha512.Sum512(certificate.Raw)
+
+	tests := []struct {
+		selector     int64
+		matchingType int64
+		expected     string
+	}{
+		{tlsaSelectorSpki, tlsaMatchingSha256, hex.EncodeToString(spkiSha256[:])},
+		{tlsaSelectorCertificate, tlsaMatchingSha512, hex.EncodeToString(certSha512[:])},
+		{tlsaSelectorSpki, tlsaMatchingFull, hex.EncodeToString(certificate.RawSubjectPublicKeyInfo)},
+	}
+
+	for _, tc := range tests {
+		data, err := tlsaAssociationData(certificatePem, tc.selector, tc.matchingType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if data != tc.expected {
+			t.Errorf("selector %d matching type %d: expected %s, got %s", tc.selector, tc.matchingType, tc.expected, data)
+		}
+
+		if err := validateTlsaData(tc.matchingType, data); err != nil {
+			t.Errorf("selector %d matching type %d: computed data is invalid: %s", tc.selector, tc.matchingType, err)
+		}
+	}
+
+	if _, err := tlsaAssociationData("not a certificate", tlsaSelectorSpki, tlsaMatchingSha256); err == nil {
+		t.Error("expected an error for an invalid certificate")
+	}
+}
+
+func Test_ValidateTlsaData(t *testing.T) {
+	invalid := map[int64]string{
+		tlsaMatchingSha256: strings.Repeat("a", 63),
+		tlsaMatchingSha512: strings.Repeat("a", 64),
+		tlsaMatchingFull:   "not hex",
+	}
+
+	for matchingType, data := range invalid {
+		if err := validateTlsaData(matchingType, data); err == nil {
+			t.Errorf("expected %q to be invalid for matching type %d", data, matchingType)
+		}
+	}
+}
+
+func Test_ParseTlsaContent(t *testing.T) {
+	tlsa, err := parseTlsaContent("3 1 1 ABCD ef01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := tlsa.content(); got != "3 1 1 abcdef01" {
+		t.Errorf("expected %q, got %q", "3 1 1 abcdef01", got)
+	}
+
+	if _, err := parseTlsaContent("3 1 abcd"); err == nil {
+		t.Error("expected an error for a record without data")
+	}
+}
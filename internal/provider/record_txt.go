@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// txtChunkSize is the maximum length of a single character-string in a TXT record
+const txtChunkSize = 255
+
+// formatTxtContent converts the logical value of a TXT record into the content sent to Porkbun. Values longer than
+// a single character-string are split into quoted chunks, e.g. `"v=DKIM1; k=rsa; p=MIIB..." "...IDAQAB"`, while
+// shorter values and content that is already correctly quoted are sent unchanged.
+func formatTxtContent(value string) string {
+	if len(value) <= txtChunkSize {
+		return value
+	}
+
+	if chunks, ok := splitTxtCharacterStrings(value); ok {
+		if !slices.ContainsFunc(chunks, func(chunk string) bool { return len(chunk) > txtChunkSize }) {
+			return value
+		}
+		// Already quoted, but with character-strings that are too long
+		value = strings.Join(chunks, "")
+	}
+
+	return strings.Join(chunkTxtValue(value), " ")
+}
+
+// chunkTxtValue splits the logical value of a TXT record into quoted character-strings of at most txtChunkSize bytes
+func chunkTxtValue(value string) []string {
+	var chunks []string
+	for len(value) > 0 {
+		end := min(txtChunkSize, len(value))
+		// Don't split a multibyte character across two chunks
+		for end < len(value) && !utf8.RuneStart(value[end]) {
+			end--
+		}
+		chunks = append(chunks, quoteTxtCharacterString(value[:end]))
+		value = value[end:]
+	}
+
+	return chunks
+}
+
+// parseTxtContent joins the quoted character-strings of a TXT record back into its logical value. Content that
+// isn't made of quoted character-strings is returned unchanged.
+func parseTxtContent(content string) string {
+	chunks, ok := splitTxtCharacterStrings(content)
+	if !ok {
+		return content
+	}
+
+	return strings.Join(chunks, "")
+}
+
+// txtContentEqual reports whether two TXT contents have the same logical value
+func txtContentEqual(a string, b string) bool {
+	return a == b || parseTxtContent(a) == parseTxtContent(b)
+}
+
+func quoteTxtCharacterString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// splitTxtCharacterStrings splits content made of one or more quoted character-strings separated by whitespace,
+// reporting false if the content is in any other form
+func splitTxtCharacterStrings(content string) ([]string, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) {
+		return nil, false
+	}
+
+	var chunks []string
+	var current strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case inQuotes && ch == '\\' && i+1 < len(content):
+			i++
+			current.WriteByte(content[i])
+		case inQuotes && ch == '"':
+			chunks = append(chunks, current.String())
+			current.Reset()
+			inQuotes = false
+		case inQuotes:
+			current.WriteByte(ch)
+		case ch == '"':
+			inQuotes = true
+		case ch == ' ' || ch == '\t':
+			continue
+		default:
+			// Text outside of quotes means this isn't a list of character-strings
+			return nil, false
+		}
+	}
+
+	if inQuotes {
+		return nil, false
+	}
+
+	return chunks, true
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_FormatTxtContent(t *testing.T) {
+	short := "v=spf1 include:_spf.porkbun.com ~all"
+	if got := formatTxtContent(short); got != short {
+		t.Errorf("expected short values to be unchanged, got %q", got)
+	}
+
+	long := "v=DKIM1; k=rsa; p=" + strings.Repeat("A", 400) + `"\`
+	formatted := formatTxtContent(long)
+
+	chunks, ok := splitTxtCharacterStrings(formatted)
+	if !ok {
+		t.Fatalf("expected quoted character-strings, got %q", formatted)
+	}
+
+	if len(chunks) != 2 {
+		t.Errorf("expected 2 chunks, got %d", len(chunks))
+	}
+
+	for _, chunk := range chunks {
+		if len(chunk) > txtChunkSize {
+			t.Errorf("chunk is %d bytes long", len(chunk))
+		}
+	}
+
+	if got := parseTxtContent(formatted); got != long {
+		t.Errorf("expected the chunks to join back to the original value, got %q", got)
+	}
+
+	if got := formatTxtContent(formatted); got != formatted {
+		t.Errorf("expected already chunked content to be unchanged, got %q", got)
+	}
+}
+
+func Test_FormatTxtContentMultibyte(t *testing.T) {
+	long := strings.Repeat("é", 200)
+
+	chunks, ok := splitTxtCharacterStrings(formatTxtContent(long))
+	if !ok {
+		t.Fatal("expected quoted character-strings")
+	}
+
+	for _, chunk := range chunks {
+		if !strings.HasPrefix(chunk, "é") || !strings.HasSuffix(chunk, "é") {
+			t.Errorf("expected chunks to be split on character boundaries, got %q", chunk)
+		}
+	}
+}
+
+func Test_TxtContentEqual(t *testing.T) {
+	if !txtContentEqual("v=spf1 -all", `"v=spf1 -all"`) {
+		t.Error("expected quoted and unquoted content to be equal")
+	}
+
+	if !txtContentEqual("foobar", `"foo" "bar"`) {
+		t.Error("expected chunked content to equal the joined value")
+	}
+
+	if txtContentEqual("foo bar", `"foo" "bar"`) {
+		t.Error("expected chunks to be joined without a separator")
+	}
+}
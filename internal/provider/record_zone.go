@@ -0,0 +1,394 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// zoneDefaultTtl is used for records without a TTL in a zone file without $TTL, and is also Porkbun's minimum
+const zoneDefaultTtl = 600
+
+// zoneHostnameTypes are the record types whose content is a single host name
+var zoneHostnameTypes = []string{"ALIAS", "CNAME", "NS", "PTR"}
+
+// zoneRecord is a record of a zone file in the form of porkbun_dns_record, with the name relative to the domain
+type zoneRecord struct {
+	name    string
+	typ     string
+	content string
+	ttl     string
+	prio    string
+}
+
+// zoneToken is a word or quoted character-string of a zone file entry
+type zoneToken struct {
+	text   string
+	quoted bool
+}
+
+// zoneEntry is a directive or resource record of a zone file, which may span several lines within parentheses
+type zoneEntry struct {
+	line int
+	// blankOwner is set when the entry starts with whitespace and so uses the owner of the previous record
+	blankOwner bool
+	tokens     []zoneToken
+}
+
+// parseZoneFile reads the records of a zone file in the format of RFC 1035 section 5 for the domain. The SOA record
+// is skipped because Porkbun manages it, and TTLs below Porkbun's minimum are raised to it.
+func parseZoneFile(text string, domain string) ([]zoneRecord, error) {
+	origin, err := normalizeDomainName(domain)
+	if err != nil {
+		return nil, err
+	}
+	if origin == "" {
+		return nil, fmt.Errorf("the origin must not be empty")
+	}
+
+	entries, err := tokenizeZoneFile(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []zoneRecord
+	defaultTtl, lastTtl, lastOwner := "", "", ""
+
+	for _, entry := range entries {
+		tokens := entry.tokens
+
+		if !entry.blankOwner && strings.HasPrefix(tokens[0].text, "$") {
+			directive := strings.ToUpper(tokens[0].text)
+			switch {
+			case directive == "$ORIGIN" && len(tokens) == 2:
+				origin = absoluteZoneName(tokens[1].text, origin)
+			case directive == "$TTL" && len(tokens) == 2:
+				ttl, ok := parseZoneTtl(tokens[1].text)
+				if !ok {
+					return nil, fmt.Errorf("line %d: invalid TTL %q", entry.line, tokens[1].text)
+				}
+				defaultTtl = ttl
+			case directive == "$ORIGIN" || directive == "$TTL":
+				return nil, fmt.Errorf("line %d: %s takes a single argument", entry.line, directive)
+			default:
+				return nil, fmt.Errorf("line %d: the %s directive is not supported", entry.line, tokens[0].text)
+			}
+			continue
+		}
+
+		owner := lastOwner
+		if !entry.blankOwner {
+			owner = absoluteZoneName(tokens[0].text, origin)
+			tokens = tokens[1:]
+		} else if owner == "" {
+			return nil, fmt.Errorf("line %d: the first record must have a name", entry.line)
+		}
+		lastOwner = owner
+
+		// The TTL and class are both optional and may come in either order
+		ttl := ""
+		for len(tokens) > 0 {
+			if strings.EqualFold(tokens[0].text, "IN") {
+				tokens = tokens[1:]
+			} else if slices.Contains([]string{"CH", "CS", "HS"}, strings.ToUpper(tokens[0].text)) {
+				return nil, fmt.Errorf("line %d: only the IN class is supported, got %s", entry.line, tokens[0].text)
+			} else if value, ok := parseZoneTtl(tokens[0].text); ok && ttl == "" {
+				ttl = value
+				tokens = tokens[1:]
+			} else {
+				break
+			}
+		}
+
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: missing the record type", entry.line)
+		}
+
+		switch {
+		case ttl != "":
+			lastTtl = ttl
+		case defaultTtl != "":
+			ttl = defaultTtl
+		case lastTtl != "":
+			ttl = lastTtl
+		default:
+			ttl = strconv.Itoa(zoneDefaultTtl)
+		}
+		if seconds, _ := strconv.Atoi(ttl); seconds < zoneDefaultTtl {
+			ttl = strconv.Itoa(zoneDefaultTtl)
+		}
+
+		typ := strings.ToUpper(tokens[0].text)
+		if typ == "SOA" {
+			continue
+		}
+
+		name, err := relativeRecordName(owner, domain)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+
+		record := zoneRecord{name: name, typ: typ, ttl: ttl, prio: "0"}
+		if err := record.setRdata(tokens[1:], origin); err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// setRdata sets the content and priority of the record from the data of its zone file entry, qualifying host names
+// with the origin
+func (r *zoneRecord) setRdata(rdata []zoneToken, origin string) error {
+	if len(rdata) == 0 {
+		return fmt.Errorf("the %s record has no data", r.typ)
+	}
+
+	fields := make([]string, 0, len(rdata))
+	for _, token := range rdata {
+		fields = append(fields, token.text)
+	}
+
+	switch {
+	case r.typ == "MX" || r.typ == "SRV":
+		count := map[string]int{"MX": 2, "SRV": 4}[r.typ]
+		if len(fields) != count {
+			return fmt.Errorf("the %s record needs %d fields, got %d", r.typ, count, len(fields))
+		}
+		if _, err := strconv.ParseUint(fields[0], 10, 16); err != nil {
+			return fmt.Errorf("invalid %s priority %q", r.typ, fields[0])
+		}
+		r.prio = fields[0]
+		fields[count-1] = absoluteZoneName(fields[count-1], origin)
+		r.content = strings.Join(fields[1:], " ")
+	case slices.Contains(zoneHostnameTypes, r.typ):
+		if len(fields) != 1 {
+			return fmt.Errorf("the %s record needs a single host name, got %d fields", r.typ, len(fields))
+		}
+		r.content = absoluteZoneName(fields[0], origin)
+	case r.typ == "TXT" || r.typ == "SPF":
+		// The character-strings make up a single value
+		r.content = strings.Join(fields, "")
+	default:
+		for i, token := range rdata {
+			if token.quoted {
+				fields[i] = quoteTxtCharacterString(token.text)
+			}
+		}
+		r.content = strings.Join(fields, " ")
+	}
+
+	return nil
+}
+
+// renderZoneFile writes the records as a zone file with names relative to the origin it is loaded with
+func renderZoneFile(records []zoneRecord) (string, error) {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 1, ' ', 0)
+
+	for i, record := range records {
+		typ := strings.ToUpper(strings.TrimSpace(record.typ))
+		if typ == "" {
+			return "", fmt.Errorf("record %d has no type", i)
+		}
+
+		name := record.name
+		if name == "" {
+			name = "@"
+		}
+		if strings.ContainsAny(name, " \t") {
+			return "", fmt.Errorf("record %d has an invalid name %q", i, name)
+		}
+
+		if strings.TrimSpace(record.content) == "" && typ != "TXT" && typ != "SPF" {
+			return "", fmt.Errorf("record %d has no content", i)
+		}
+
+		rdata := record.content
+		switch {
+		case typ == "MX" || typ == "SRV":
+			fields := strings.Fields(record.content)
+			fields[len(fields)-1] = qualifiedZoneName(fields[len(fields)-1])
+			prio := record.prio
+			if prio == "" {
+				prio = "0"
+			}
+			rdata = prio + " " + strings.Join(fields, " ")
+		case slices.Contains(zoneHostnameTypes, typ):
+			rdata = qualifiedZoneName(strings.TrimSpace(record.content))
+		case typ == "TXT" || typ == "SPF":
+			chunks := chunkTxtValue(parseTxtContent(record.content))
+			if len(chunks) == 0 {
+				chunks = []string{`""`}
+			}
+			rdata = strings.Join(chunks, " ")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\tIN\t%s\t%s\n", name, record.ttl, typ, rdata)
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// tokenizeZoneFile splits a zone file into its entries, removing comments and joining lines within parentheses
+func tokenizeZoneFile(text string) ([]zoneEntry, error) {
+	var entries []zoneEntry
+	var current *zoneEntry
+	line, depth := 1, 0
+
+	for i := 0; i < len(text); {
+		ch := text[i]
+
+		if current == nil {
+			current = &zoneEntry{line: line, blankOwner: ch == ' ' || ch == '\t'}
+		}
+
+		switch {
+		case ch == '\n':
+			line++
+			i++
+			if depth == 0 {
+				if len(current.tokens) > 0 {
+					entries = append(entries, *current)
+				}
+				current = nil
+			}
+		case ch == ' ' || ch == '\t' || ch == '\r':
+			i++
+		case ch == ';':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case ch == '(':
+			depth++
+			i++
+		case ch == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parenthesis", line)
+			}
+			depth--
+			i++
+		case ch == '"':
+			value, end, ok := readZoneQuoted(text, i)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated quoted string", line)
+			}
+			line += strings.Count(text[i:end], "\n")
+			current.tokens = append(current.tokens, zoneToken{text: value, quoted: true})
+			i = end
+		default:
+			start := i
+			for i < len(text) && !strings.ContainsRune(" \t\r\n;()\"", rune(text[i])) {
+				if text[i] == '\\' && i+1 < len(text) {
+					i++
+				}
+				i++
+			}
+			current.tokens = append(current.tokens, zoneToken{text: text[start:i]})
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parenthesis", line)
+	}
+	if current != nil && len(current.tokens) > 0 {
+		entries = append(entries, *current)
+	}
+
+	return entries, nil
+}
+
+// readZoneQuoted reads the quoted character-string starting at text[start], resolving escapes like \" and \065,
+// and returns its value and the index after the closing quote
+func readZoneQuoted(text string, start int) (string, int, bool) {
+	var value strings.Builder
+
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			return value.String(), i + 1, true
+		case '\\':
+			if i+1 >= len(text) {
+				return "", 0, false
+			}
+			if i+3 < len(text) {
+				if code, err := strconv.ParseUint(text[i+1:i+4], 10, 8); err == nil {
+					value.WriteByte(byte(code))
+					i += 3
+					continue
+				}
+			}
+			i++
+			value.WriteByte(text[i])
+		default:
+			value.WriteByte(text[i])
+		}
+	}
+
+	return "", 0, false
+}
+
+// parseZoneTtl reads a TTL in seconds or in the BIND form with units like 1h30m, returning it in seconds
+func parseZoneTtl(value string) (string, bool) {
+	if value == "" || value[0] < '0' || value[0] > '9' {
+		return "", false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 31); err == nil {
+		return strconv.FormatUint(seconds, 10), true
+	}
+
+	units := map[byte]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	var total, number uint64
+	digits := false
+
+	for _, ch := range []byte(strings.ToLower(value)) {
+		if ch >= '0' && ch <= '9' {
+			number = number*10 + uint64(ch-'0')
+			digits = true
+			continue
+		}
+		unit, ok := units[ch]
+		if !ok || !digits {
+			return "", false
+		}
+		total += number * unit
+		number, digits = 0, false
+	}
+	if digits || total > 1<<31-1 {
+		return "", false
+	}
+
+	return strconv.FormatUint(total, 10), true
+}
+
+// absoluteZoneName qualifies a name of a zone file with the origin, without the trailing dot. `@` is the origin
+// itself and the root stays `.`.
+func absoluteZoneName(name string, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case name == ".":
+		return name
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	}
+
+	return name + "." + origin
+}
+
+// qualifiedZoneName adds the trailing dot to a fully qualified host name so a zone file doesn't append its origin
+func qualifiedZoneName(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+
+	return name + "."
+}
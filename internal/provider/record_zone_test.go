@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testZoneFile = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.porkbun.com. admin.example.com. (
+		2024010101 ; serial
+		7200 3600 1209600 3600 )
+@		A	192.0.2.1
+		AAAA	2001:db8::1
+www	900	IN	CNAME	@
+mail	IN 7200	A	192.0.2.2
+@		MX	10 mail
+@		MX	20 mx.example.net.
+_sip._tcp	SRV	10 5 5060 sip
+@		TXT	"v=spf1 mx " "-all" ; joined
+@		CAA	0 issue "letsencrypt.org"
+short	60	A	192.0.2.3
+
+$ORIGIN dev.example.com.
+*		A	192.0.2.4
+`
+
+func Test_ParseZoneFile(t *testing.T) {
+	records, err := parseZoneFile(testZoneFile, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []zoneRecord{
+		{name: "", typ: "A", content: "192.0.2.1", ttl: "3600", prio: "0"},
+		{name: "", typ: "AAAA", content: "2001:db8::1", ttl: "3600", prio: "0"},
+		{name: "www", typ: "CNAME", content: "example.com", ttl: "900", prio: "0"},
+		{name: "mail", typ: "A", content: "192.0.2.2", ttl: "7200", prio: "0"},
+		{name: "", typ: "MX", content: "mail.example.com", ttl: "3600", prio: "10"},
+		{name: "", typ: "MX", content: "mx.example.net", ttl: "3600", prio: "20"},
+		{name: "_sip._tcp", typ: "SRV", content: "5 5060 sip.example.com", ttl: "3600", prio: "10"},
+		{name: "", typ: "TXT", content: "v=spf1 mx -all", ttl: "3600", prio: "0"},
+		{name: "", typ: "CAA", content: `0 issue "letsencrypt.org"`, ttl: "3600", prio: "0"},
+		{name: "short", typ: "A", content: "192.0.2.3", ttl: "600", prio: "0"},
+		{name: "*.dev", typ: "A", content: "192.0.2.4", ttl: "3600", prio: "0"},
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %+v, got %+v", expected, records)
+	}
+}
+
+func Test_ParseZoneFileWithoutTtl(t *testing.T) {
+	records, err := parseZoneFile("a 3600 A 192.0.2.1\nb A 192.0.2.2\n", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without $TTL a record uses the TTL of the one before it
+	if records[1].ttl != "3600" {
+		t.Errorf("expected the previous TTL, got %q", records[1].ttl)
+	}
+
+	records, err = parseZoneFile("a A 192.0.2.1", "example.com")
+	if err != nil || records[0].ttl != "600" {
+		t.Errorf("expected the default TTL, got %+v: %v", records, err)
+	}
+}
+
+func Test_ParseZoneFileErrors(t *testing.T) {
+	for _, text := range []string{
+		"$INCLUDE other.zone",
+		"\tA 192.0.2.1",
+		"www",
+		"www CH A 192.0.2.1",
+		"@ MX mail",
+		"@ MX high mail",
+		"@ TXT \"unterminated",
+		"@ TXT ( \"open\"",
+		"@ A 192.0.2.1 )",
+		"other.com. A 192.0.2.1",
+		"$TTL forever",
+		"@ CNAME",
+	} {
+		if _, err := parseZoneFile(text, "example.com"); err == nil {
+			t.Errorf("expected %q to be an error", text)
+		}
+	}
+}
+
+func Test_ParseZoneTtl(t *testing.T) {
+	tests := map[string]string{
+		"600":   "600",
+		"1h":    "3600",
+		"1h30m": "5400",
+		"2D":    "172800",
+		"1w":    "604800",
+	}
+
+	for value, expected := range tests {
+		actual, ok := parseZoneTtl(value)
+		if !ok || actual != expected {
+			t.Errorf("%q: expected %q, got %q", value, expected, actual)
+		}
+	}
+
+	for _, value := range []string{"", "h", "1x", "1h30", "A", "IN"} {
+		if _, ok := parseZoneTtl(value); ok {
+			t.Errorf("expected %q to be invalid", value)
+		}
+	}
+}
+
+func Test_RenderZoneFile(t *testing.T) {
+	text, err := renderZoneFile([]zoneRecord{
+		{name: "", typ: "A", content: "192.0.2.1", ttl: "600", prio: "0"},
+		{name: "www", typ: "cname", content: "example.com", ttl: "900"},
+		{name: "", typ: "MX", content: "mail.example.com", ttl: "600", prio: "10"},
+		{name: "_sip._tcp", typ: "SRV", content: "5 5060 sip.example.com", ttl: "600", prio: "10"},
+		{name: "", typ: "TXT", content: `say "hi"`, ttl: "600"},
+		{name: "empty", typ: "TXT", content: "", ttl: "600"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		`@         600 IN A     192.0.2.1`,
+		`www       900 IN CNAME example.com.`,
+		`@         600 IN MX    10 mail.example.com.`,
+		`_sip._tcp 600 IN SRV   10 5 5060 sip.example.com.`,
+		`@         600 IN TXT   "say \"hi\""`,
+		`empty     600 IN TXT   ""`,
+	}, "\n") + "\n"
+
+	if text != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, text)
+	}
+
+	if _, err := renderZoneFile([]zoneRecord{{name: "www", typ: "A"}}); err == nil {
+		t.Error("expected a record without content to be an error")
+	}
+}
+
+func Test_ZoneFileRoundTrip(t *testing.T) {
+	long := strings.Repeat("p", 300)
+	records := []zoneRecord{
+		{name: "", typ: "MX", content: "mail.example.com", ttl: "600", prio: "10"},
+		{name: "default._domainkey", typ: "TXT", content: "v=DKIM1; k=rsa; p=" + long, ttl: "3600", prio: "0"},
+		{name: "www", typ: "CNAME", content: "example.com", ttl: "600", prio: "0"},
+	}
+
+	text, err := renderZoneFile(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := parseZoneFile(text, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, records) {
+		t.Errorf("expected %+v, got %+v from\n%s", records, parsed, text)
+	}
+}
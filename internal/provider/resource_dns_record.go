@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
-	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -19,10 +20,22 @@ import (
 	"github.com/nrdcg/porkbun"
 )
 
+// recordLookupTimeout bounds looking for a created record after the create operation itself timed out
+const recordLookupTimeout = 30 * time.Second
+
+// Ways to handle records with the same name and type that already exist when creating a record
+const (
+	onConflictError   = "error"
+	onConflictAdopt   = "adopt"
+	onConflictReplace = "replace"
+)
+
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = &porkbunDnsRecordResource{}
-	_ resource.ResourceWithImportState = &porkbunDnsRecordResource{}
+	_ resource.Resource                   = &porkbunDnsRecordResource{}
+	_ resource.ResourceWithImportState    = &porkbunDnsRecordResource{}
+	_ resource.ResourceWithValidateConfig = &porkbunDnsRecordResource{}
+	_ resource.ResourceWithModifyPlan     = &porkbunDnsRecordResource{}
 )
 
 func NewPorkbunDnsRecordResource() resource.Resource {
@@ -31,19 +44,23 @@ func NewPorkbunDnsRecordResource() resource.Resource {
 
 type porkbunDnsRecordResource struct {
 	client   *porkbun.Client
-	provider *provider.Provider
+	provider *porkbunProvider
 }
 
 // porkbunDnsRecordResourceModel describes the data model
 type porkbunDnsRecordResourceModel struct {
-	Id      types.String `tfsdk:"id"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
-	Content types.String `tfsdk:"content"`
-	Ttl     types.String `tfsdk:"ttl"`
-	Notes   types.String `tfsdk:"notes"`
-	Prio    types.String `tfsdk:"prio"`
-	Domain  types.String `tfsdk:"domain"`
+	Id         types.String      `tfsdk:"id"`
+	Name       types.String      `tfsdk:"name"`
+	Type       types.String      `tfsdk:"type"`
+	Content    types.String      `tfsdk:"content"`
+	Ttl        types.String      `tfsdk:"ttl"`
+	Notes      types.String      `tfsdk:"notes"`
+	Prio       types.String      `tfsdk:"prio"`
+	Domain     types.String      `tfsdk:"domain"`
+	OnConflict types.String      `tfsdk:"on_conflict"`
+	Caa        *porkbunCaaModel  `tfsdk:"caa"`
+	Svcb       *porkbunSvcbModel `tfsdk:"svcb"`
+	Tlsa       *porkbunTlsaModel `tfsdk:"tlsa"`
 }
 
 func (r *porkbunDnsRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -59,18 +76,18 @@ func (r *porkbunDnsRecordResource) Schema(ctx context.Context, req resource.Sche
 			"name": schema.StringAttribute{
 				Computed:            true,
 				Optional:            true,
-				MarkdownDescription: "The subdomain for the record itself without the base domain",
+				MarkdownDescription: "The subdomain for the record itself without the base domain. Internationalized names can be given in Unicode or punycode",
 				Default:             stringdefault.StaticString(""),
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplaceIfConfigured(),
+					requiresReplaceIfNameChanged(),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"domain": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "The base domain to to create the record on",
+				MarkdownDescription: "The base domain to to create the record on. Internationalized domains can be given in Unicode or punycode",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplaceIfConfigured(),
+					requiresReplaceIfNameChanged(),
 				},
 			},
 			"id": schema.StringAttribute{
@@ -112,37 +129,184 @@ func (r *porkbunDnsRecordResource) Schema(ctx context.Context, req resource.Sche
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"on_conflict": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "What to do when creating the record while records with the same name and type already exist. `error` fails, `adopt` takes over an existing record with the same content and `replace` edits the existing record in place. By default the record is created regardless",
+				Validators: []validator.String{
+					OneOf(onConflictError, onConflictAdopt, onConflictReplace),
+				},
+			},
 			"content": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The content of the record",
+				Computed:            true,
+				MarkdownDescription: "The content of the record. Computed when one of the `caa`, `svcb` or `tlsa` blocks is used. `TXT` values longer than 255 characters are split into quoted strings automatically",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"caa":  caaBlock(),
+			"svcb": svcbBlock(),
+			"tlsa": tlsaBlock(),
+		},
+	}
+}
+
+func (r porkbunDnsRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunDnsRecordResourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, attribute := range []struct {
+		name  string
+		value types.String
+	}{{"domain", data.Domain}, {"name", data.Name}} {
+		if attribute.value.IsNull() || attribute.value.IsUnknown() {
+			continue
+		}
+		if _, err := normalizeRecordName(attribute.value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(attribute.name), fmt.Sprintf("Invalid %s", attribute.name), err.Error())
+		}
+	}
+
+	if data.Caa != nil {
+		resp.Diagnostics.Append(validateStructuredContent(data, "caa", "CAA")...)
+		resp.Diagnostics.Append(data.Caa.validate(path.Root("caa"))...)
+	}
+
+	if data.Svcb != nil {
+		resp.Diagnostics.Append(validateStructuredContent(data, "svcb", "HTTPS", "SVCB")...)
+		resp.Diagnostics.Append(data.Svcb.validate(ctx, path.Root("svcb"))...)
+	}
+
+	if data.Tlsa != nil {
+		resp.Diagnostics.Append(validateStructuredContent(data, "tlsa", "TLSA")...)
+		resp.Diagnostics.Append(data.Tlsa.validate(path.Root("tlsa"))...)
+	}
+
+	if strings.EqualFold(data.Type.ValueString(), "TXT") {
+		if lookups, ok := countSpfLookups(data.Content.ValueString()); ok && lookups > spfMaxLookups {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("content"),
+				"Too many SPF lookups",
+				fmt.Sprintf("The SPF record needs %d DNS lookups before counting those of included records, receivers fail SPF checks that need more than %d.", lookups, spfMaxLookups),
+			)
+		}
 	}
 }
 
+// validateStructuredContent ensures a structured content block is only used with its record type and without content
+func validateStructuredContent(data porkbunDnsRecordResourceModel, block string, recordTypes ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !data.Content.IsNull() {
+		diags.AddAttributeError(
+			path.Root("content"),
+			"Conflicting content",
+			fmt.Sprintf("content cannot be set together with the %s block, it is computed from the block instead", block),
+		)
+	}
+
+	if data.Type.IsUnknown() {
+		return diags
+	}
+
+	for _, recordType := range recordTypes {
+		if strings.EqualFold(data.Type.ValueString(), recordType) {
+			return diags
+		}
+	}
+
+	diags.AddAttributeError(
+		path.Root("type"),
+		"Invalid record type",
+		fmt.Sprintf("the %s block can only be used with records of type %s", block, strings.Join(recordTypes, " or ")),
+	)
+
+	return diags
+}
+
+func (r porkbunDnsRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data, config porkbunDnsRecordResourceModel
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	diags = req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case data.Caa != nil:
+		if data.Caa.isKnown() {
+			data.Content = types.StringValue(data.Caa.content())
+		} else {
+			data.Content = types.StringUnknown()
+		}
+	case data.Svcb != nil:
+		if data.Svcb.isKnown() {
+			content, diags := data.Svcb.content(ctx)
+			resp.Diagnostics.Append(diags...)
+			data.Content = types.StringValue(content)
+		} else {
+			data.Content = types.StringUnknown()
+		}
+	case data.Tlsa != nil:
+		if data.Tlsa.isKnown() {
+			resp.Diagnostics.Append(data.Tlsa.computeData()...)
+			data.Content = types.StringValue(data.Tlsa.content())
+		} else {
+			if !data.Tlsa.CertificatePem.IsNull() {
+				data.Tlsa.Data = types.StringUnknown()
+			}
+			data.Content = types.StringUnknown()
+		}
+	case config.Content.IsNull():
+		// content is only computed from structured blocks, without one it stays empty
+		data.Content = types.StringNull()
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
 func (r *porkbunDnsRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*porkbun.Client)
+	p, ok := req.ProviderData.(*porkbunProvider)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *porkbunProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = p.client
+	r.provider = p
 }
 
 func (r porkbunDnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data porkbunDnsRecordResourceModel
-	// attempts := req.Provider
 
 	diags := req.Plan.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -151,29 +315,261 @@ func (r porkbunDnsRecordResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	ctx, cancel := r.provider.retry.operationContext(ctx, operationCreate)
+	defer cancel()
+
+	domain, name, err := data.apiNames()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record name", fmt.Sprintf("Error: %s", err))
+		return
+	}
+
 	record := porkbun.Record{
-		Name:    data.Name.ValueString(),
+		Name:    name,
 		Type:    data.Type.ValueString(),
-		Content: data.Content.ValueString(),
+		Content: recordContent(data),
 		TTL:     data.Ttl.ValueString(),  // Minimum is 600 according to porkbun docs
 		Prio:    data.Prio.ValueString(), // Doesn't work on .com?
 		Notes:   data.Notes.ValueString(),
 	}
 
-	id, err := r.client.CreateRecord(ctx, data.Domain.ValueString(), record)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating DNS Record",
-			fmt.Sprintf("Error: %s", err),
-		)
+	id, diags := r.resolveConflict(ctx, data.OnConflict.ValueString(), domain, record)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	data.Id = types.StringValue(fmt.Sprint(id))
+	if id == "" {
+		id, diags = r.createRecord(ctx, domain, record)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(id)
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
 
+// resolveConflict handles existing records with the same name and type as the record according to on_conflict. It
+// returns the ID of the record that was taken over, or an empty ID when the record still has to be created.
+func (r porkbunDnsRecordResource) resolveConflict(ctx context.Context, onConflict string, domain string, record porkbun.Record) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if onConflict == "" {
+		return "", diags
+	}
+
+	conflicts, err := r.conflictingRecords(ctx, domain, record)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Could not retrieve records for %s.", domain), fmt.Sprintf("Error: %s", err))
+		return "", diags
+	}
+
+	if len(conflicts) == 0 {
+		return "", diags
+	}
+
+	var ids []string
+	var existing *porkbun.Record
+	for i, conflict := range conflicts {
+		ids = append(ids, conflict.ID)
+		if existing == nil && recordContentEqual(record.Type, conflict.Content, record.Content) {
+			existing = &conflicts[i]
+		}
+	}
+
+	switch {
+	case onConflict == onConflictError:
+		diags.AddAttributeError(
+			path.Root("on_conflict"),
+			"Conflicting DNS Record",
+			fmt.Sprintf("%s records already exist for this name with the IDs %s. Import them, or set on_conflict to adopt or replace to take one over.", record.Type, strings.Join(ids, ", ")),
+		)
+		return "", diags
+	case existing == nil && onConflict == onConflictAdopt:
+		diags.AddAttributeError(
+			path.Root("on_conflict"),
+			"Conflicting DNS Record",
+			fmt.Sprintf("%s records already exist for this name with the IDs %s, but none has the same content. Set on_conflict to replace to edit the existing record instead.", record.Type, strings.Join(ids, ", ")),
+		)
+		return "", diags
+	case existing == nil && len(conflicts) > 1:
+		diags.AddAttributeError(
+			path.Root("on_conflict"),
+			"Conflicting DNS Record",
+			fmt.Sprintf("%s records already exist for this name with the IDs %s. Only a single record can be replaced, import the one to keep and remove the others.", record.Type, strings.Join(ids, ", ")),
+		)
+		return "", diags
+	case existing == nil:
+		existing = &conflicts[0]
+	}
+
+	tflog.Debug(ctx, "Taking over existing record", map[string]interface{}{
+		"domain":      domain,
+		"id":          existing.ID,
+		"on_conflict": onConflict,
+	})
+
+	// Bring everything else of the existing record in line with the configuration
+	if recordContentEqual(record.Type, existing.Content, record.Content) &&
+		existing.TTL == record.TTL && existing.Prio == record.Prio && existing.Notes == record.Notes {
+		return existing.ID, diags
+	}
+
+	intId, err := strconv.Atoi(existing.ID)
+	if err != nil {
+		diags.AddError("Error converting ID to a string", fmt.Sprintf("Error: %s", err))
+		return "", diags
+	}
+
+	err = r.client.EditRecord(ctx, domain, intId, record)
+	r.provider.records.Invalidate(domain)
+	if err != nil {
+		diags.AddError("Error updating the record", fmt.Sprintf("Error %s", err))
+		return "", diags
+	}
+
+	return existing.ID, diags
+}
+
+// createRecord creates the record and returns its ID. When it is unknown whether Porkbun created the record, e.g.
+// after a timeout, an identical record is looked up and adopted before the create is retried, so retries don't
+// leave duplicate records behind.
+func (r porkbunDnsRecordResource) createRecord(ctx context.Context, domain string, record porkbun.Record) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for attempt := 0; ; attempt++ {
+		id, err := r.client.CreateRecord(ctx, domain, record)
+		r.provider.records.Invalidate(domain)
+		if err == nil {
+			return strconv.Itoa(id), diags
+		}
+
+		if !isAmbiguousError(err) {
+			diags.AddError("Error creating DNS Record", fmt.Sprintf("Error: %s", err))
+			return "", diags
+		}
+
+		tflog.Warn(ctx, "Creating the record failed, looking for an identical record", map[string]interface{}{
+			"domain":  domain,
+			"attempt": attempt,
+			"error":   err.Error(),
+		})
+
+		existing, lookupErr := r.findIdenticalRecord(ctx, domain, record)
+		if lookupErr != nil {
+			diags.AddError(
+				"Error creating DNS Record",
+				fmt.Sprintf("Error: %s\n\nIt is unknown whether the record was created, looking it up failed with: %s", err, lookupErr),
+			)
+			return "", diags
+		}
+
+		if existing != nil {
+			diags.AddWarning(
+				"Adopted existing DNS Record",
+				fmt.Sprintf("Creating the record failed with %q, but an identical record with ID %s exists and is used instead.", err, existing.ID),
+			)
+			return existing.ID, diags
+		}
+
+		if !r.provider.retry.retryAmbiguousCreates || attempt >= r.provider.MaxRetries {
+			diags.AddError("Error creating DNS Record", fmt.Sprintf("Error: %s", err))
+			return "", diags
+		}
+
+		if err := sleepUntil(ctx, time.Now().Add(r.provider.retry.wait(attempt))); err != nil {
+			diags.AddError("Error creating DNS Record", fmt.Sprintf("Error: %s", err))
+			return "", diags
+		}
+	}
+}
+
+// findIdenticalRecord returns the record of the domain with the same name, type and content, if there is one
+func (r porkbunDnsRecordResource) findIdenticalRecord(ctx context.Context, domain string, record porkbun.Record) (*porkbun.Record, error) {
+	// The lookup is still worth doing when the create timed out, otherwise the record is left behind untracked
+	ctx, cancel := lookupContext(ctx)
+	defer cancel()
+
+	records, err := r.conflictingRecords(ctx, domain, record)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range records {
+		if recordContentEqual(record.Type, existing.Content, record.Content) {
+			return &existing, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// findRecord returns the record of the domain with the ID, or nil when there is none
+func (r porkbunDnsRecordResource) findRecord(ctx context.Context, domain string, id string) (*porkbun.Record, error) {
+	ctx, cancel := lookupContext(ctx)
+	defer cancel()
+
+	records, err := r.provider.records.Records(ctx, r.client, domain, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range records {
+		if existing.ID == id {
+			return &existing, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// lookupContext returns a context for looking up what a failed request did, which still has time left when the
+// request failed because the operation timed out
+func lookupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() == nil {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(context.WithoutCancel(ctx), recordLookupTimeout)
+}
+
+// recordApplied reports whether the existing record already has the content and settings of the record
+func recordApplied(record porkbun.Record, existing porkbun.Record) bool {
+	return recordContentEqual(record.Type, existing.Content, record.Content) &&
+		(record.TTL == "" || existing.TTL == record.TTL) &&
+		(record.Prio == "" || existing.Prio == record.Prio) &&
+		existing.Notes == record.Notes
+}
+
+// conflictingRecords returns the records of the domain with the same name and type as the record
+func (r porkbunDnsRecordResource) conflictingRecords(ctx context.Context, domain string, record porkbun.Record) ([]porkbun.Record, error) {
+	records, err := r.provider.records.Records(ctx, r.client, domain, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Porkbun returns the full name of the record
+	name, err := recordFqdn(record.Name, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []porkbun.Record
+	for _, existing := range records {
+		if strings.EqualFold(existing.Name, name) && strings.EqualFold(existing.Type, record.Type) {
+			conflicts = append(conflicts, existing)
+		}
+	}
+
+	return conflicts, nil
+}
+
 func (r porkbunDnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data porkbunDnsRecordResourceModel
 
@@ -183,7 +579,16 @@ func (r porkbunDnsRecordResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	getRecordsResult, err := r.getRecords(ctx, data.Domain.ValueString())
+	ctx, cancel := r.provider.retry.operationContext(ctx, operationRead)
+	defer cancel()
+
+	domain, configuredName, err := data.apiNames()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record name", fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	getRecordsResult, err := r.getRecords(ctx, domain, data.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf(
@@ -194,21 +599,44 @@ func (r porkbunDnsRecordResource) Read(ctx context.Context, req resource.ReadReq
 		)
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Found records: %s", getRecordsResult))
+	tflog.Debug(ctx, "Found records", map[string]interface{}{
+		"domain": data.Domain.ValueString(),
+		"count":  len(getRecordsResult),
+	})
+	found := false
 	for _, record := range getRecordsResult {
-		tflog.Info(ctx, fmt.Sprintf("This record is: %s", record.ID))
 		if record.ID == data.Id.ValueString() {
-			// This is to handle if there's no subdomain
-			if data.Domain.ValueString() == record.Name {
-				data.Name = types.StringValue("")
-			} else {
-				// The API returns the full record as the name so we'll strip off the domain at the end to keep it consistent
-				data.Name = types.StringValue(strings.ReplaceAll(record.Name, fmt.Sprintf(".%s", data.Domain.ValueString()), ""))
+			found = true
+			if data.Type.IsNull() {
+				readImportedRecord(&data, record)
 			}
 
+			// The API returns the full record as the name so we'll strip off the domain at the end to keep it consistent
+			name, err := relativeRecordName(record.Name, domain)
+			if err != nil {
+				resp.Diagnostics.AddError("Could not read the record name", fmt.Sprintf("Error: %s", err))
+				return
+			}
+			// Porkbun returns internationalized names in punycode, the configured form is kept when it's equivalent
+			if name != configuredName || data.Name.IsNull() {
+				data.Name = types.StringValue(name)
+			}
+
+			resp.Diagnostics.Append(flattenRecordContent(ctx, &data, record)...)
 		}
 	}
 
+	if !found && data.Type.IsNull() && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError(
+			"Record not found",
+			fmt.Sprintf("No record with the ID %s exists in %s.", data.Id.ValueString(), data.Domain.ValueString()),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
@@ -225,10 +653,19 @@ func (r porkbunDnsRecordResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
+	ctx, cancel := r.provider.retry.operationContext(ctx, operationUpdate)
+	defer cancel()
+
+	domain, name, err := data.apiNames()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record name", fmt.Sprintf("Error: %s", err))
+		return
+	}
+
 	record := porkbun.Record{
-		Name:    data.Name.ValueString(),
+		Name:    name,
 		Type:    data.Type.ValueString(),
-		Content: data.Content.ValueString(),
+		Content: recordContent(data),
 		TTL:     data.Ttl.ValueString(),  // Minimum is 600 according to porkbun docs
 		Prio:    data.Prio.ValueString(), // Doesn't work on .com?
 		Notes:   data.Notes.ValueString(),
@@ -242,7 +679,20 @@ func (r porkbunDnsRecordResource) Update(ctx context.Context, req resource.Updat
 		)
 	}
 
-	err = r.client.EditRecord(ctx, data.Domain.ValueString(), intId, record)
+	err = r.client.EditRecord(ctx, domain, intId, record)
+	r.provider.records.Invalidate(domain)
+	if err != nil && isAmbiguousError(err) {
+		// Editing is only done when the record shows the change, the edit may have been applied anyway
+		existing, lookupErr := r.findRecord(ctx, domain, recordId)
+		if lookupErr == nil && existing != nil && recordApplied(record, *existing) {
+			tflog.Warn(ctx, "Updating the record failed, but the record was updated", map[string]interface{}{
+				"domain": domain,
+				"id":     recordId,
+				"error":  err.Error(),
+			})
+			err = nil
+		}
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating the record",
@@ -269,6 +719,9 @@ func (r porkbunDnsRecordResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
+	ctx, cancel := r.provider.retry.operationContext(ctx, operationDelete)
+	defer cancel()
+
 	intId, err := strconv.Atoi(state.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -277,7 +730,26 @@ func (r porkbunDnsRecordResource) Delete(ctx context.Context, req resource.Delet
 		)
 	}
 
-	err = r.client.DeleteRecord(ctx, state.Domain.ValueString(), intId)
+	domain, _, err := state.apiNames()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record name", fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	err = r.client.DeleteRecord(ctx, domain, intId)
+	r.provider.records.Invalidate(domain)
+	if err != nil {
+		// A retried delete fails for the record that the first attempt already deleted
+		existing, lookupErr := r.findRecord(ctx, domain, state.Id.ValueString())
+		if lookupErr == nil && existing == nil {
+			tflog.Warn(ctx, "Deleting the record failed, but the record is gone", map[string]interface{}{
+				"domain": domain,
+				"id":     state.Id.ValueString(),
+				"error":  err.Error(),
+			})
+			err = nil
+		}
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting record",
@@ -290,12 +762,151 @@ func (r porkbunDnsRecordResource) Delete(ctx context.Context, req resource.Delet
 	}
 }
 
+// ImportState takes an ID of the form <domain>/<id>, as Read needs the domain to find the record
 func (r porkbunDnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	domain, id, ok := strings.Cut(req.ID, "/")
+	if !ok || domain == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an ID of the form <domain>/<id>, e.g. example.com/123456, got %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// readImportedRecord sets the attributes of a record that was just imported, whose state only has its domain and ID
+func readImportedRecord(data *porkbunDnsRecordResourceModel, record porkbun.Record) {
+	data.Type = types.StringValue(record.Type)
+
+	content := record.Content
+	if strings.EqualFold(record.Type, "TXT") {
+		content = parseTxtContent(content)
+	}
+	data.Content = types.StringValue(content)
+
+	ttl, prio := record.TTL, record.Prio
+	if ttl == "" {
+		ttl = "600"
+	}
+	if prio == "" {
+		prio = "0"
+	}
+	data.Ttl = types.StringValue(ttl)
+	data.Prio = types.StringValue(prio)
+
+	if record.Notes != "" {
+		data.Notes = types.StringValue(record.Notes)
+	}
+}
+
+// apiNames returns the domain and name of the record in the ASCII form Porkbun uses, with internationalized labels
+// in punycode
+func (m porkbunDnsRecordResourceModel) apiNames() (string, string, error) {
+	domain, err := normalizeDomainName(m.Domain.ValueString())
+	if err != nil {
+		return "", "", err
+	}
+
+	name, err := normalizeRecordName(m.Name.ValueString())
+	if err != nil {
+		return "", "", err
+	}
+
+	return domain, name, nil
+}
+
+// recordContent returns the content of the record in the form Porkbun expects it
+func recordContent(data porkbunDnsRecordResourceModel) string {
+	if strings.EqualFold(data.Type.ValueString(), "TXT") {
+		return formatTxtContent(data.Content.ValueString())
+	}
+
+	return data.Content.ValueString()
+}
+
+// recordContentEqual reports whether two contents of a record only differ in how Porkbun formats them
+func recordContentEqual(recordType string, a string, b string) bool {
+	switch strings.ToUpper(recordType) {
+	case "CAA":
+		return caaContentEqual(a, b)
+	case "HTTPS", "SVCB":
+		return svcbContentEqual(a, b)
+	case "TLSA":
+		return tlsaContentEqual(a, b)
+	case "TXT":
+		return txtContentEqual(a, b)
+	}
+
+	return a == b
+}
+
+// flattenRecordContent refreshes the content of structured records from the API, ignoring differences
+// that are only caused by how Porkbun formats the content
+func flattenRecordContent(ctx context.Context, data *porkbunDnsRecordResourceModel, record porkbun.Record) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	switch {
+	case data.Caa != nil:
+		caa, err := parseCaaContent(record.Content)
+		if err != nil {
+			diags.AddError("Could not parse CAA record", fmt.Sprintf("Error: %s", err))
+			return diags
+		}
+		// Tags are case-insensitive, keep the configured spelling
+		if strings.EqualFold(data.Caa.Tag.ValueString(), caa.Tag.ValueString()) {
+			caa.Tag = data.Caa.Tag
+		}
+		data.Caa = caa
+		data.Content = types.StringValue(caa.content())
+	case data.Svcb != nil:
+		svcb, err := parseSvcbContent(record.Content)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Could not parse %s record", record.Type), fmt.Sprintf("Error: %s", err))
+			return diags
+		}
+		// Keep the configured spelling of e.g. IPv6 hints as long as the record is the same
+		if content, d := data.Svcb.content(ctx); d.HasError() || content != svcb.String() {
+			data.Svcb = svcb.toModel()
+		}
+		data.Content = types.StringValue(svcb.String())
+	case data.Tlsa != nil:
+		tlsa, err := parseTlsaContent(record.Content)
+		if err != nil {
+			diags.AddError("Could not parse TLSA record", fmt.Sprintf("Error: %s", err))
+			return diags
+		}
+		// The certificate isn't part of the record, a changed digest shows up as a diff against it on the next plan
+		if data.Tlsa.content() != tlsa.content() {
+			tlsa.CertificatePem = data.Tlsa.CertificatePem
+			data.Tlsa = tlsa
+		}
+		data.Content = types.StringValue(tlsa.content())
+	case strings.EqualFold(record.Type, "CAA"):
+		if !caaContentEqual(data.Content.ValueString(), record.Content) {
+			data.Content = types.StringValue(record.Content)
+		}
+	case strings.EqualFold(record.Type, "HTTPS"), strings.EqualFold(record.Type, "SVCB"):
+		if !svcbContentEqual(data.Content.ValueString(), record.Content) {
+			data.Content = types.StringValue(record.Content)
+		}
+	case strings.EqualFold(record.Type, "TLSA"):
+		if !tlsaContentEqual(data.Content.ValueString(), record.Content) {
+			data.Content = types.StringValue(record.Content)
+		}
+	case strings.EqualFold(record.Type, "TXT"):
+		if !txtContentEqual(data.Content.ValueString(), record.Content) {
+			data.Content = types.StringValue(parseTxtContent(record.Content))
+		}
+	}
+
+	return diags
 }
 
-func (r porkbunDnsRecordResource) getRecords(ctx context.Context, domain string) ([]porkbun.Record, error) {
-	records, err := r.client.RetrieveRecords(ctx, domain)
+func (r porkbunDnsRecordResource) getRecords(ctx context.Context, domain string, id string) ([]porkbun.Record, error) {
+	records, err := r.provider.records.Records(ctx, r.client, domain, id)
 	if err != nil {
 		return []porkbun.Record{}, err
 	}
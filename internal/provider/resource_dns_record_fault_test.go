@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// faultOperation is a CRUD operation of the record resource and the step that runs it
+type faultOperation struct {
+	name     string
+	endpoint string
+	config   string
+	planOnly bool
+	err      *regexp.Regexp
+	check    func(server *porkbuntest.Server) func(*terraform.State) error
+}
+
+var faultOperations = []faultOperation{
+	{
+		name:     "create",
+		endpoint: "dns/create",
+		config:   testRecordConfigFaults("0.0.0.1"),
+		err:      regexp.MustCompile(`Error creating DNS Record`),
+		check: func(server *porkbuntest.Server) func(*terraform.State) error {
+			return testAccCheckRecordContent(server, "0.0.0.1")
+		},
+	},
+	{
+		name:     "read",
+		endpoint: "dns/retrieve",
+		config:   testRecordConfigFaults("0.0.0.1"),
+		planOnly: true,
+		err:      regexp.MustCompile(`Could not retrieve records`),
+	},
+	{
+		name:     "update",
+		endpoint: "dns/edit",
+		config:   testRecordConfigFaults("0.0.0.2"),
+		err:      regexp.MustCompile(`Error updating the record`),
+		check: func(server *porkbuntest.Server) func(*terraform.State) error {
+			return testAccCheckRecordContent(server, "0.0.0.2")
+		},
+	},
+	{
+		name:     "delete",
+		endpoint: "dns/delete",
+		config:   testProviderConfigFaults(),
+		err:      regexp.MustCompile(`Error deleting record`),
+		check:    testAccCheckRecordsDestroyed,
+	},
+}
+
+// The provider is configured with a request timeout of a second, so slow responses take twice as long
+var faultTests = []struct {
+	name  string
+	fault porkbuntest.Fault
+	// fails lists the operations that fail under the fault, they succeed when they are run again
+	fails []string
+}{
+	{
+		name:  "rate limited",
+		fault: porkbuntest.Fault{Kind: porkbuntest.FaultRateLimit, Delay: time.Second, Count: 2},
+	},
+	{
+		name:  "error status",
+		fault: porkbuntest.Fault{Kind: porkbuntest.FaultErrorStatus, Message: "Something went wrong."},
+		fails: []string{"create", "read", "update", "delete"},
+	},
+	{
+		name:  "truncated response",
+		fault: porkbuntest.Fault{Kind: porkbuntest.FaultTruncated},
+		fails: []string{"read"},
+	},
+	{
+		name:  "slow response",
+		fault: porkbuntest.Fault{Kind: porkbuntest.FaultSlow, Delay: 2 * time.Second},
+	},
+	{
+		name:  "failed after apply",
+		fault: porkbuntest.Fault{Kind: porkbuntest.FaultFailAfterApply},
+	},
+	{
+		name:  "server error",
+		fault: porkbuntest.Fault{Kind: porkbuntest.FaultHTTPError, Status: http.StatusBadGateway, Message: "Bad Gateway"},
+	},
+}
+
+func Test_RecordSurvivesFaults(t *testing.T) {
+	for _, tc := range faultTests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t)
+
+			var steps []resource.TestStep
+			for _, op := range faultOperations {
+				fails := false
+				for _, name := range tc.fails {
+					fails = fails || name == op.name
+				}
+
+				fault := tc.fault
+				fault.Endpoint = op.endpoint
+
+				step := resource.TestStep{
+					PreConfig: func() { server.Script(fault) },
+					Config:    op.config,
+					PlanOnly:  op.planOnly,
+				}
+
+				if fails {
+					step.ExpectError = op.err
+					steps = append(steps, step)
+
+					// Running the operation again without the fault recovers
+					step = resource.TestStep{Config: op.config, PlanOnly: op.planOnly}
+				}
+
+				checks := []resource.TestCheckFunc{testAccCheckFaultsUsed(server)}
+				if op.check != nil {
+					checks = append(checks, op.check(server))
+				}
+				step.Check = resource.ComposeAggregateTestCheckFunc(checks...)
+
+				steps = append(steps, step)
+			}
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+				CheckDestroy:             testAccCheckRecordsDestroyed(server),
+				Steps:                    steps,
+			})
+		})
+	}
+}
+
+// testAccCheckRecordContent checks that the test domain holds exactly one record with the content, i.e. retries
+// didn't create duplicates
+func testAccCheckRecordContent(server *porkbuntest.Server, content string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		records := server.Records(testDomain)
+		if len(records) != 1 || records[0].Content != content {
+			return fmt.Errorf("expected a single record with content %s, found %v", content, records)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckFaultsUsed checks that the step ran into the scripted faults
+func testAccCheckFaultsUsed(server *porkbuntest.Server) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		if pending := server.PendingFaults(); pending != 0 {
+			return fmt.Errorf("expected all faults to be used, %d are left", pending)
+		}
+
+		return nil
+	}
+}
+
+func testProviderConfigFaults() string {
+	return `
+provider "porkbun" {
+  request_timeout = "1s"
+
+  retry {
+    min_wait = "10ms"
+    max_wait = "100ms"
+  }
+}
+`
+}
+
+func testRecordConfigFaults(content string) string {
+	return testProviderConfigFaults() + fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = "faults"
+  domain = "providertest.top"
+  content = "%s"
+  type = "A"
+}
+`, content)
+}
@@ -2,16 +2,23 @@ package provider
 
 import (
 	"fmt"
-	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func Test_CreateRecordWithSubdomainSuccess(t *testing.T) {
-	lastOctet := randomOctet()
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testRecordConfigWithSubdomain(lastOctet),
@@ -25,9 +32,11 @@ func Test_CreateRecordWithSubdomainSuccess(t *testing.T) {
 }
 
 func Test_CreateRecordWithoutSubdomainSuccess(t *testing.T) {
-	lastOctet := randomOctet()
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testRecordConfigNoSubdomain(lastOctet),
@@ -41,9 +50,11 @@ func Test_CreateRecordWithoutSubdomainSuccess(t *testing.T) {
 }
 
 func Test_CreateRecordSetProviderCredsWithVars(t *testing.T) {
-	lastOctet := randomOctet()
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testRecordSetProviderCredsWithVars(lastOctet),
@@ -56,14 +67,327 @@ func Test_CreateRecordSetProviderCredsWithVars(t *testing.T) {
 	})
 }
 
+func Test_CreateCaaRecordSuccess(t *testing.T) {
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigCaa(lastOctet),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", `0 issue "letsencrypt.org"`),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "caa.flags", "0"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "caa.tag", "issue"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "caa.value", "letsencrypt.org"),
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateHttpsRecordSuccess(t *testing.T) {
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigHttps(lastOctet),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", fmt.Sprintf("1 . alpn=h3,h2 port=443 ipv4hint=0.0.0.%v", lastOctet)),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "svcb.alpn.#", "2"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "svcb.port", "443"),
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateTlsaRecordFromCertificateSuccess(t *testing.T) {
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
+	// A fixed certificate keeps the content of the record the same when replaying a cassette
+	certificatePem, err := os.ReadFile("testdata/tlsa_certificate.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigTlsa(lastOctet, string(certificatePem)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("porkbun_dns_record.test", "tlsa.data", func(value string) error {
+						return validateTlsaData(tlsaMatchingSha256, value)
+					}),
+					resource.TestMatchResourceAttr("porkbun_dns_record.test", "content", regexp.MustCompile(`^3 1 1 [0-9a-f]{64}$`)),
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateLongTxtRecordSuccess(t *testing.T) {
+	acc := newAccTest(t)
+	lastOctet := acc.randomOctet()
+	value := fmt.Sprintf("v=DKIM1; k=rsa; p=%s", strings.Repeat("A", 400))
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.factories,
+		CheckDestroy:             acc.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigTxt(lastOctet, value),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", value),
+				),
+			},
+			{
+				// Refreshing the record must not show the chunks as a diff
+				Config:   testRecordConfigTxt(lastOctet, value),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// Porkbun only knows internationalized domains in punycode, so this test only runs against the fake API
+func Test_CreateRecordWithInternationalizedNamesSuccess(t *testing.T) {
+	server := newTestServer(t)
+	server.AddZone("xn--mnchen-3ya.de")
+
+	var id string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigInternationalized("bücher", "münchen.de"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "name", "bücher"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "domain", "münchen.de"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["porkbun_dns_record.test"].Primary.ID
+
+						records := server.Records("xn--mnchen-3ya.de")
+						if len(records) != 1 || records[0].Name != "xn--bcher-kva.xn--mnchen-3ya.de" {
+							return fmt.Errorf("expected a single record named in punycode, found %v", records)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Switching to punycode updates the record in place
+				Config: testRecordConfigInternationalized("xn--bcher-kva", "xn--mnchen-3ya.de"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "name", "xn--bcher-kva"),
+					func(s *terraform.State) error {
+						if actual := s.RootModule().Resources["porkbun_dns_record.test"].Primary.ID; actual != id {
+							return fmt.Errorf("expected the record %s to be kept, got %s", id, actual)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateRecordAdoptsRecordAfterServerError(t *testing.T) {
+	server := newTestServer(t)
+	// The record is created, but the client can't tell
+	server.Script(porkbuntest.Fault{Endpoint: "dns/create", Kind: porkbuntest.FaultFailAfterApply})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		CheckDestroy:             testAccCheckRecordsDestroyed(server),
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigWithSubdomain(1),
+				Check: func(s *terraform.State) error {
+					records := server.Records(testDomain)
+					if len(records) != 1 {
+						return fmt.Errorf("expected exactly one record, got %d", len(records))
+					}
+
+					return resource.TestCheckResourceAttr("porkbun_dns_record.test", "id", records[0].ID)(s)
+				},
+			},
+		},
+	})
+}
+
+func Test_CreateRecordWithoutOnConflictCreatesRecord(t *testing.T) {
+	server := newTestServer(t)
+	existing := server.AddRecord(testDomain, porkbuntest.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigOnConflict("www", "A", "0.0.0.2", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckRecordCount(server, 2),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["porkbun_dns_record.test"].Primary.ID; id == existing {
+							return fmt.Errorf("expected a new record to be created, got the existing record %s", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateRecordOnConflictAdopt(t *testing.T) {
+	server := newTestServer(t)
+	existing := server.AddRecord(testDomain, porkbuntest.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config:      testRecordConfigOnConflict("www", "A", "0.0.0.2", "adopt"),
+				ExpectError: regexp.MustCompile(`none\s+has\s+the\s+same\s+content`),
+			},
+			{
+				Config: testRecordConfigOnConflict("www", "A", "0.0.0.1", "adopt"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckRecordCount(server, 1),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "id", existing),
+					func(*terraform.State) error {
+						if edits := testCountRequests(server, "dns/edit/"); edits != 0 {
+							return fmt.Errorf("expected an identical record to be adopted as is, got %d edits", edits)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateRecordOnConflictReplace(t *testing.T) {
+	server := newTestServer(t)
+	existing := server.AddRecord(testDomain, porkbuntest.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigOnConflict("www", "A", "0.0.0.2", "replace"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckRecordCount(server, 1),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "id", existing),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", "0.0.0.2"),
+					func(*terraform.State) error {
+						if records := server.Records(testDomain); records[0].Content != "0.0.0.2" {
+							return fmt.Errorf("expected the existing record to be edited, got content %q", records[0].Content)
+						}
+						if edits := testCountRequests(server, "dns/edit/"); edits != 1 {
+							return fmt.Errorf("expected a single edit, got %d", edits)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateRecordOnConflictWithSeveralRecords(t *testing.T) {
+	server := newTestServer(t)
+	first := server.AddRecord(testDomain, porkbuntest.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+	second := server.AddRecord(testDomain, porkbuntest.Record{Name: "www", Type: "A", Content: "0.0.0.3"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config:      testRecordConfigOnConflict("www", "A", "0.0.0.2", "error"),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`with\s+the\s+IDs\s+%s,\s+%s`, first, second)),
+			},
+			{
+				Config:      testRecordConfigOnConflict("www", "A", "0.0.0.2", "replace"),
+				ExpectError: regexp.MustCompile(`Only\s+a\s+single\s+record\s+can\s+be\s+replaced`),
+			},
+			{
+				// The record with the same content is taken over, the other one is left alone
+				Config: testRecordConfigOnConflict("www", "A", "0.0.0.3", "replace"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckRecordCount(server, 2),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "id", second),
+				),
+			},
+		},
+	})
+}
+
+func Test_CreateRecordOnConflictReplacesParkingRecord(t *testing.T) {
+	server := newTestServer(t)
+	// Porkbun points new domains at its parking page
+	server.AddRecord(testDomain, porkbuntest.Record{Name: "", Type: "ALIAS", Content: "pixie.porkbun.com"})
+	parking := server.AddRecord(testDomain, porkbuntest.Record{Name: "*", Type: "CNAME", Content: "pixie.porkbun.com"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL()),
+		Steps: []resource.TestStep{
+			{
+				Config: testRecordConfigOnConflict("*", "CNAME", "example.com", "replace"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckRecordCount(server, 2),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "id", parking),
+					func(*terraform.State) error {
+						for _, record := range server.Records(testDomain) {
+							if record.Type == "ALIAS" && record.Content != "pixie.porkbun.com" {
+								return fmt.Errorf("expected the parking ALIAS to be left alone, got %q", record.Content)
+							}
+							if record.Type == "CNAME" && record.Content != "example.com" {
+								return fmt.Errorf("expected the parking CNAME to be replaced, got %q", record.Content)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testCheckRecordCount(server *porkbuntest.Server, expected int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if records := server.Records(testDomain); len(records) != expected {
+			return fmt.Errorf("expected %d records, got %d", expected, len(records))
+		}
+		return nil
+	}
+}
+
+// testCountRequests returns how many requests the fake API received for endpoints starting with the prefix
+func testCountRequests(server *porkbuntest.Server, prefix string) int {
+	count := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request.Endpoint, prefix) {
+			count++
+		}
+	}
+
+	return count
+}
+
 func testRecordConfigNoSubdomain(randomIp int) string {
 	return fmt.Sprintf(`
 resource "porkbun_dns_record" "test" {
   domain = "providertest.top"
   content = "0.0.0.%v"
   type = "A"
+  notes = %q
 }
-`, randomIp)
+`, randomIp, testRecordNotes)
 }
 
 func testRecordConfigWithSubdomain(randomIp int) string {
@@ -73,8 +397,9 @@ resource "porkbun_dns_record" "test" {
   domain = "providertest.top"
   content = "0.0.0.%v"
   type = "A"
+  notes = %q
 }
-`, randomIp, randomIp)
+`, randomIp, randomIp, testRecordNotes)
 }
 
 func testRecordSetProviderCredsWithVars(randomIp int) string {
@@ -92,10 +417,101 @@ resource "porkbun_dns_record" "test" {
   domain = "providertest.top"
   content = "0.0.0.%v"
   type = "A"
+  notes = %q
+}
+`, randomIp, randomIp, testRecordNotes)
+}
+
+func testRecordConfigCaa(randomIp int) string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = "%v-caa"
+  domain = "providertest.top"
+  type = "CAA"
+
+  caa {
+    tag   = "issue"
+    value = "letsencrypt.org"
+  }
+  notes = %q
 }
-`, randomIp, randomIp)
+`, randomIp, testRecordNotes)
 }
 
-func randomOctet() int {
-	return rand.Intn(255-0) + 0
+func testRecordConfigHttps(randomIp int) string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = "%v-https"
+  domain = "providertest.top"
+  type = "HTTPS"
+
+  svcb {
+    priority = 1
+    target   = "."
+    alpn     = ["h3", "h2"]
+    port     = 443
+    ipv4hint = ["0.0.0.%v"]
+  }
+  notes = %q
+}
+`, randomIp, randomIp, testRecordNotes)
+}
+
+func testRecordConfigTlsa(randomIp int, certificatePem string) string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = "_25._tcp.%v-mail"
+  domain = "providertest.top"
+  type = "TLSA"
+
+  tlsa {
+    usage           = 3
+    selector        = 1
+    matching_type   = 1
+    certificate_pem = <<-EOT
+%sEOT
+  }
+  notes = %q
+}
+`, randomIp, certificatePem, testRecordNotes)
+}
+
+func testRecordConfigTxt(randomIp int, value string) string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = "%v._domainkey"
+  domain = "providertest.top"
+  content = %q
+  type = "TXT"
+  notes = %q
+}
+`, randomIp, value, testRecordNotes)
+}
+
+func testRecordConfigOnConflict(name string, recordType string, content string, onConflict string) string {
+	onConflictAttribute := "null"
+	if onConflict != "" {
+		onConflictAttribute = strconv.Quote(onConflict)
+	}
+
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = %q
+  domain = "providertest.top"
+  content = %q
+  type = %q
+  on_conflict = %s
+}
+`, name, content, recordType, onConflictAttribute)
+}
+
+func testRecordConfigInternationalized(name string, domain string) string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  name = %q
+  domain = %q
+  content = "0.0.0.1"
+  type = "A"
+}
+`, name, domain)
 }
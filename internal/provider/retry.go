@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nrdcg/porkbun"
+)
+
+const (
+	defaultRetryMinWait = 1 * time.Second
+	defaultRetryMaxWait = 30 * time.Second
+)
+
+type operation string
+
+const (
+	operationCreate operation = "create"
+	operationRead   operation = "read"
+	operationUpdate operation = "update"
+	operationDelete operation = "delete"
+)
+
+type operationContextKey struct{}
+
+// porkbunRetryModel describes the retry block of the provider configuration
+type porkbunRetryModel struct {
+	MinWait               types.String `tfsdk:"min_wait"`
+	MaxWait               types.String `tfsdk:"max_wait"`
+	Jitter                types.Bool   `tfsdk:"jitter"`
+	RetryAmbiguousCreates types.Bool   `tfsdk:"retry_ambiguous_creates"`
+	CreateTimeout         types.String `tfsdk:"create_timeout"`
+	ReadTimeout           types.String `tfsdk:"read_timeout"`
+	UpdateTimeout         types.String `tfsdk:"update_timeout"`
+	DeleteTimeout         types.String `tfsdk:"delete_timeout"`
+}
+
+// retryPolicy controls how failed requests are retried and how long each operation may take
+type retryPolicy struct {
+	minWait               time.Duration
+	maxWait               time.Duration
+	jitter                bool
+	retryAmbiguousCreates bool
+	timeouts              map[operation]time.Duration
+}
+
+func retryBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Controls how failed requests to the Porkbun API are retried",
+		Attributes: map[string]schema.Attribute{
+			"min_wait": schema.StringAttribute{
+				MarkdownDescription: "Minimum time to wait before retrying a request, defaults to `1s`",
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
+			"max_wait": schema.StringAttribute{
+				MarkdownDescription: "Maximum time to wait before retrying a request, defaults to `30s`",
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
+			"jitter": schema.BoolAttribute{
+				MarkdownDescription: "Randomize the time between retries so concurrent requests don't retry at the same time, defaults to `false`",
+				Optional:            true,
+			},
+			"retry_ambiguous_creates": schema.BoolAttribute{
+				MarkdownDescription: "Retry creating a record when it is unknown whether Porkbun created it, e.g. after a timeout, and no identical record exists. Defaults to `true`",
+				Optional:            true,
+			},
+			"create_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time creating a record may take including retries",
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
+			"read_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time reading a record may take including retries",
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
+			"update_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time updating a record may take including retries",
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
+			"delete_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time deleting a record may take including retries",
+				Optional:            true,
+				Validators:          []validator.String{Duration()},
+			},
+		},
+	}
+}
+
+// newRetryPolicy builds the retry policy from the retry block, which may be omitted
+func newRetryPolicy(m *porkbunRetryModel) (retryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := retryPolicy{
+		minWait:               defaultRetryMinWait,
+		maxWait:               defaultRetryMaxWait,
+		retryAmbiguousCreates: true,
+		timeouts:              map[operation]time.Duration{},
+	}
+
+	if m == nil {
+		return policy, diags
+	}
+
+	parse := func(name string, value types.String, target *time.Duration) {
+		if value.IsNull() || value.IsUnknown() {
+			return
+		}
+
+		d, err := time.ParseDuration(value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("retry").AtName(name), "Invalid duration", err.Error())
+			return
+		}
+		*target = d
+	}
+
+	parse("min_wait", m.MinWait, &policy.minWait)
+	parse("max_wait", m.MaxWait, &policy.maxWait)
+
+	for op, value := range map[operation]types.String{
+		operationCreate: m.CreateTimeout,
+		operationRead:   m.ReadTimeout,
+		operationUpdate: m.UpdateTimeout,
+		operationDelete: m.DeleteTimeout,
+	} {
+		var timeout time.Duration
+		parse(string(op)+"_timeout", value, &timeout)
+		if timeout > 0 {
+			policy.timeouts[op] = timeout
+		}
+	}
+
+	if policy.minWait > policy.maxWait {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("min_wait"),
+			"Invalid retry wait",
+			fmt.Sprintf("min_wait (%s) cannot be longer than max_wait (%s)", policy.minWait, policy.maxWait),
+		)
+	}
+
+	if !m.Jitter.IsNull() {
+		policy.jitter = m.Jitter.ValueBool()
+	}
+
+	if !m.RetryAmbiguousCreates.IsNull() {
+		policy.retryAmbiguousCreates = m.RetryAmbiguousCreates.ValueBool()
+	}
+
+	return policy, diags
+}
+
+// configure applies the policy to the retrying client
+func (p retryPolicy) configure(client *retryablehttp.Client) {
+	client.RetryWaitMin = p.minWait
+	client.RetryWaitMax = p.maxWait
+	client.CheckRetry = p.checkRetry
+	client.Backoff = p.backoff
+}
+
+// operationContext marks the context with the operation and applies its timeout, if any
+func (p retryPolicy) operationContext(ctx context.Context, op operation) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, operationContextKey{}, op)
+
+	if timeout, ok := p.timeouts[op]; ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+func (p retryPolicy) checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	op, _ := ctx.Value(operationContextKey{}).(operation)
+	if op == operationCreate && isAmbiguousFailure(resp, err) {
+		// The resource looks for the record before retrying, the original error or response is returned by the client
+		return false, nil
+	}
+
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+func (p retryPolicy) backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	wait := retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+
+	// Waits requested by Porkbun are honoured as they are
+	if !p.jitter || (resp != nil && resp.Header.Get("Retry-After") != "") || wait <= min {
+		return wait
+	}
+
+	return min + time.Duration(rand.Int63n(int64(wait-min)+1))
+}
+
+// wait returns how long to wait before the given attempt of an operation that is retried outside the client
+func (p retryPolicy) wait(attempt int) time.Duration {
+	return p.backoff(p.minWait, p.maxWait, attempt, nil)
+}
+
+// isAmbiguousError reports whether an error returned by the Porkbun client leaves it unknown if the request was processed
+func isAmbiguousError(err error) bool {
+	// Porkbun answered, so the request was processed and rejected
+	var status porkbun.Status
+	if errors.As(err, &status) {
+		return false
+	}
+
+	var serverErr *porkbun.ServerError
+	if errors.As(err, &serverErr) {
+		return isAmbiguousFailure(&http.Response{StatusCode: serverErr.StatusCode}, nil)
+	}
+
+	return isAmbiguousFailure(nil, err)
+}
+
+// isAmbiguousFailure reports whether a failed request may still have been processed by Porkbun
+func isAmbiguousFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		// The request never left when the connection couldn't be established
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return false
+		}
+
+		var dnsErr *net.DNSError
+		return !errors.As(err, &dnsErr)
+	}
+
+	if resp == nil {
+		return false
+	}
+
+	// Porkbun rate limits with a 503 before processing the request
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return false
+	}
+
+	return resp.StatusCode >= 500
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nrdcg/porkbun"
+)
+
+func Test_RetryPolicyAmbiguousCreates(t *testing.T) {
+	// Ambiguous creates are never retried by the client, the resource looks for the record first
+	policy, diags := newRetryPolicy(nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	createCtx, cancel := policy.operationContext(context.Background(), operationCreate)
+	defer cancel()
+	readCtx, cancel := policy.operationContext(context.Background(), operationRead)
+	defer cancel()
+
+	timeout := errors.New("context deadline exceeded (Client.Timeout exceeded while awaiting headers)")
+	badGateway := &http.Response{StatusCode: http.StatusBadGateway}
+	rateLimited := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	refused := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{"create timeout", createCtx, nil, timeout, false},
+		{"create bad gateway", createCtx, badGateway, nil, false},
+		{"create rate limited", createCtx, rateLimited, nil, true},
+		{"create connection refused", createCtx, nil, refused, true},
+		{"read timeout", readCtx, nil, timeout, true},
+		{"read bad gateway", readCtx, badGateway, nil, true},
+	}
+
+	for _, tc := range tests {
+		retry, _ := policy.checkRetry(tc.ctx, tc.resp, tc.err)
+		if retry != tc.expected {
+			t.Errorf("%s: expected retry to be %v", tc.name, tc.expected)
+		}
+	}
+}
+
+func Test_IsAmbiguousError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"api error", porkbun.Status{Status: "ERROR", Message: "Invalid type."}, false},
+		{"rate limited", &porkbun.ServerError{StatusCode: http.StatusServiceUnavailable}, false},
+		{"bad request", &porkbun.ServerError{StatusCode: http.StatusBadRequest}, false},
+		{"bad gateway", &porkbun.ServerError{StatusCode: http.StatusBadGateway}, true},
+		{"connection refused", fmt.Errorf("failed to call API: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), false},
+		{"timeout", fmt.Errorf("failed to call API: %w", context.DeadlineExceeded), true},
+		{"truncated response", fmt.Errorf("failed to unmarshal response: %w", errors.New("unexpected end of JSON input")), true},
+	}
+
+	for _, tc := range tests {
+		if actual := isAmbiguousError(tc.err); actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func Test_RetryPolicyFromBlock(t *testing.T) {
+	policy, diags := newRetryPolicy(&porkbunRetryModel{
+		MinWait:       types.StringValue("500ms"),
+		MaxWait:       types.StringValue("5s"),
+		Jitter:        types.BoolValue(true),
+		CreateTimeout: types.StringValue("2m"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if policy.minWait != 500*time.Millisecond || policy.maxWait != 5*time.Second || !policy.retryAmbiguousCreates {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if wait := policy.backoff(policy.minWait, policy.maxWait, attempt, nil); wait < policy.minWait || wait > policy.maxWait {
+			t.Errorf("attempt %d: wait %s is outside of the configured bounds", attempt, wait)
+		}
+	}
+
+	ctx, cancel := policy.operationContext(context.Background(), operationCreate)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("expected the create timeout to apply")
+	}
+
+	_, diags = newRetryPolicy(&porkbunRetryModel{MinWait: types.StringValue("1m"), MaxWait: types.StringValue("1s")})
+	if !diags.HasError() {
+		t.Error("expected min_wait longer than max_wait to be an error")
+	}
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// sharedAccount is the rate limit budget and the record cache of a Porkbun account. Porkbun rate limits per API key,
+// so every configured provider instance in the plugin process using the same keys, e.g. through provider aliases,
+// shares them. Sharing the cache means a record written through one alias is never read stale through another.
+type sharedAccount struct {
+	limiter           *rateLimiter
+	records           *recordCache
+	requestsPerSecond float64
+	burst             int
+	instances         map[*porkbunProvider]struct{}
+}
+
+// sharedAccounts holds the shared accounts keyed by accountKey for the life of the plugin process
+var sharedAccounts = struct {
+	sync.Mutex
+	accounts map[string]*sharedAccount
+}{accounts: map[string]*sharedAccount{}}
+
+// accountKey identifies an account without keeping the keys themselves around
+func accountKey(baseURL string, apiKey string, secretKey string) string {
+	sum := sha256.Sum256([]byte(baseURL + "\x00" + apiKey + "\x00" + secretKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// sharedLimiter returns the limiter of the account, or a new one with the given limits when no configured provider
+// instance uses the account yet. A new limiter is only shared once the instance joins the account.
+func sharedLimiter(key string, requestsPerSecond float64, burst int) *rateLimiter {
+	sharedAccounts.Lock()
+	defer sharedAccounts.Unlock()
+
+	if account, ok := sharedAccounts.accounts[key]; ok {
+		return account.limiter
+	}
+
+	return newRateLimiter(requestsPerSecond, burst)
+}
+
+// joinSharedAccount registers a configured provider instance with the account and returns the account. The first
+// instance creates the account with its limiter, record cache and limits, later instances use those of the first one.
+func joinSharedAccount(ctx context.Context, p *porkbunProvider, key string, limiter *rateLimiter, records *recordCache, requestsPerSecond float64, burst int) (*sharedAccount, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	sharedAccounts.Lock()
+	defer sharedAccounts.Unlock()
+
+	account, ok := sharedAccounts.accounts[key]
+	if !ok {
+		account = &sharedAccount{
+			limiter:           limiter,
+			records:           records,
+			requestsPerSecond: requestsPerSecond,
+			burst:             burst,
+			instances:         map[*porkbunProvider]struct{}{},
+		}
+		sharedAccounts.accounts[key] = account
+	} else {
+		// The plugin process may outlive an operation, records cached for an earlier one may be stale
+		account.records.InvalidateAll()
+	}
+
+	// An instance that is configured again may have switched to other keys
+	for otherKey, other := range sharedAccounts.accounts {
+		delete(other.instances, p)
+		if len(other.instances) == 0 && other != account {
+			delete(sharedAccounts.accounts, otherKey)
+		}
+	}
+	account.instances[p] = struct{}{}
+
+	if len(account.instances) == 1 {
+		return account, diags
+	}
+
+	tflog.Info(ctx, "Provider configurations share the rate limit and record cache of their Porkbun account", map[string]interface{}{
+		"instances":           len(account.instances),
+		"requests_per_second": account.requestsPerSecond,
+		"burst":               account.burst,
+	})
+
+	if account.requestsPerSecond != requestsPerSecond || account.burst != burst {
+		diags.AddWarning(
+			"Conflicting Porkbun rate limits",
+			fmt.Sprintf(
+				"requests_per_second = %v and burst = %d are ignored, the provider configuration that was configured first for this API key set %v and %d.",
+				requestsPerSecond, burst, account.requestsPerSecond, account.burst,
+			),
+		)
+	}
+
+	return account, diags
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_JoinSharedAccount(t *testing.T) {
+	ctx := context.Background()
+	baseURL := "https://shared.example.com/api/json/v3/"
+	key := accountKey(baseURL, "pk1_shared", "sk1_shared")
+
+	first, second, other := &porkbunProvider{}, &porkbunProvider{}, &porkbunProvider{}
+
+	account, diags := joinSharedAccount(ctx, first, key, sharedLimiter(key, 2, 5), newRecordCache(), 2, 5)
+	if diags.WarningsCount() != 0 {
+		t.Errorf("expected no warnings for the first instance, got %v", diags)
+	}
+
+	// Configuring the same instance again doesn't count it twice
+	if _, diags := joinSharedAccount(ctx, first, key, sharedLimiter(key, 2, 5), newRecordCache(), 2, 5); diags.WarningsCount() != 0 {
+		t.Errorf("expected no warnings when configuring the first instance again, got %v", diags)
+	}
+
+	if sharedLimiter(key, 10, 5) != account.limiter {
+		t.Error("expected the limiter of the account for an instance that is being configured")
+	}
+
+	account.records.records["shared.example.com"] = nil
+	shared, diags := joinSharedAccount(ctx, second, key, newRateLimiter(10, 5), newRecordCache(), 10, 5)
+	if shared.limiter != account.limiter || shared.records != account.records {
+		t.Error("expected instances with the same keys to share the limiter and the record cache")
+	}
+	if _, ok := shared.records.records["shared.example.com"]; ok {
+		t.Error("expected the records cached before an instance joins to be dropped")
+	}
+	if diags.WarningsCount() != 1 || diags[0].Summary() != "Conflicting Porkbun rate limits" {
+		t.Errorf("expected a warning about the ignored limits only, got %v", diags)
+	}
+
+	otherKey := accountKey(baseURL, "pk1_shared", "sk1_other")
+	if separate, _ := joinSharedAccount(ctx, other, otherKey, sharedLimiter(otherKey, 2, 5), newRecordCache(), 2, 5); separate == account {
+		t.Error("expected instances with a different secret key not to share the account")
+	}
+
+	// Switching the keys leaves the previous account
+	joinSharedAccount(ctx, second, otherKey, sharedLimiter(otherKey, 2, 5), newRecordCache(), 2, 5)
+	if instances := len(sharedAccounts.accounts[key].instances); instances != 1 {
+		t.Errorf("expected one instance left on the account, got %d", instances)
+	}
+}
+
+func Test_SharedLimiterWithoutInstances(t *testing.T) {
+	key := accountKey("https://unused.example.com/api/json/v3/", "pk1_unused", "sk1_unused")
+
+	// An instance that fails to configure never joins, so its limiter isn't kept
+	if sharedLimiter(key, 2, 5) == sharedLimiter(key, 2, 5) {
+		t.Error("expected a new limiter for an account without instances")
+	}
+
+	sharedAccounts.Lock()
+	_, ok := sharedAccounts.accounts[key]
+	sharedAccounts.Unlock()
+
+	if ok {
+		t.Error("expected no account to be registered before an instance joins")
+	}
+}
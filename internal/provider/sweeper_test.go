@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/nrdcg/porkbun"
+)
+
+// testRecordNotes tags the records the acceptance tests create, so the sweeper finds them whatever their name
+const testRecordNotes = "terraform-provider-porkbun acceptance test"
+
+// testRecordName matches the names of the records the acceptance tests create, relative to the test domain
+var testRecordName = regexp.MustCompile(`^(_25\._tcp\.)?\d{1,3}(-foo|-caa|-https|-mail|\._domainkey)$`)
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("porkbun_dns_record", &resource.Sweeper{
+		Name: "porkbun_dns_record",
+		F:    sweepRecords,
+	})
+}
+
+// sweepRecords deletes the records left in the test domain by failed acceptance tests. Porkbun has no regions, run
+// it with `go test ./internal/provider -sweep=all` and the keys in PORKBUN_API_KEY and PORKBUN_SECRET_KEY.
+func sweepRecords(_ string) error {
+	apiKey, secretKey := os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_KEY")
+	if apiKey == "" || secretKey == "" {
+		return fmt.Errorf("PORKBUN_API_KEY and PORKBUN_SECRET_KEY must be set to sweep %s", testDomain)
+	}
+
+	client := porkbun.New(secretKey, apiKey)
+	if baseUrl := os.Getenv("PORKBUN_BASE_URL"); baseUrl != "" {
+		var err error
+		if client.BaseURL, err = url.Parse(baseUrl); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	records, err := client.RetrieveRecords(ctx, testDomain)
+	if err != nil {
+		return fmt.Errorf("retrieving records of %s: %w", testDomain, err)
+	}
+
+	for _, record := range records {
+		if !isTestRecord(record) {
+			continue
+		}
+
+		id, err := strconv.Atoi(record.ID)
+		if err != nil {
+			return fmt.Errorf("invalid ID of record %s: %w", record.Name, err)
+		}
+
+		log.Printf("[INFO] Deleting %s record %s (%s)", record.Type, record.Name, record.ID)
+		if err := client.DeleteRecord(ctx, testDomain, id); err != nil {
+			return fmt.Errorf("deleting record %s: %w", record.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// isTestRecord reports whether an acceptance test created the record
+func isTestRecord(record porkbun.Record) bool {
+	if record.Notes == testRecordNotes {
+		return true
+	}
+
+	name := strings.TrimSuffix(strings.ToLower(record.Name), "."+testDomain)
+	return testRecordName.MatchString(name)
+}
+
+func Test_IsTestRecord(t *testing.T) {
+	tests := map[string]bool{
+		"42-foo.providertest.top":            true,
+		"7-caa.providertest.top":             true,
+		"_25._tcp.255-mail.providertest.top": true,
+		"12._domainkey.providertest.top":     true,
+		"www.providertest.top":               false,
+		"42-foo.example.com":                 false,
+		"providertest.top":                   false,
+		"1234-foo.providertest.top":          false,
+		"_25._tcp.mail.providertest.top":     false,
+		"prefix.42-foo.providertest.top":     false,
+	}
+
+	for name, expected := range tests {
+		if actual := isTestRecord(porkbun.Record{Name: name}); actual != expected {
+			t.Errorf("%s: expected %v, got %v", name, expected, actual)
+		}
+	}
+
+	if !isTestRecord(porkbun.Record{Name: "providertest.top", Notes: testRecordNotes}) {
+		t.Error("expected a record with the test notes to be swept")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
@@ -53,3 +55,94 @@ func (v ttlAtLeast600Validator) ValidateString(ctx context.Context, request vali
 func TtlAtLeast600() validator.String {
 	return ttlAtLeast600Validator{}
 }
+
+var _ validator.String = oneOfValidator{}
+
+type oneOfValidator struct {
+	values          []string
+	caseInsensitive bool
+}
+
+// Description describes the validation in plain text formatting.
+func (validator oneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(validator.values, ", "))
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (validator oneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return validator.Description(ctx)
+}
+
+// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v oneOfValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if request.ConfigValue.IsUnknown() || request.ConfigValue.IsNull() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed || v.caseInsensitive && strings.EqualFold(value, allowed) {
+			return
+		}
+	}
+
+	response.Diagnostics.AddAttributeError(
+		request.Path,
+		"invalid value",
+		fmt.Sprintf("provided value %q is not one of: %s", value, strings.Join(v.values, ", ")),
+	)
+}
+
+func OneOf(values ...string) validator.String {
+	return oneOfValidator{values: values}
+}
+
+// OneOfCaseInsensitive is like OneOf but ignores the case of the value
+func OneOfCaseInsensitive(values ...string) validator.String {
+	return oneOfValidator{values: values, caseInsensitive: true}
+}
+
+var _ validator.String = durationValidator{}
+
+type durationValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (validator durationValidator) Description(_ context.Context) string {
+	return "value must be a duration such as 30s or 2m"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (validator durationValidator) MarkdownDescription(ctx context.Context) string {
+	return validator.Description(ctx)
+}
+
+// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v durationValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if request.ConfigValue.IsUnknown() || request.ConfigValue.IsNull() {
+		return
+	}
+
+	d, err := time.ParseDuration(request.ConfigValue.ValueString())
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"invalid duration",
+			fmt.Sprint(err),
+		)
+		return
+	}
+
+	if d < 0 {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"invalid duration",
+			fmt.Sprintf("provided duration %s is negative", d),
+		)
+	}
+}
+
+func Duration() validator.String {
+	return durationValidator{}
+}
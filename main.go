@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -25,6 +28,13 @@ var version string = "dev" // goreleaser can also pass the specific commit if yo
 // commit  string = ""
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(context.Background(), os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -43,3 +53,29 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// runExport writes the records of a domain as porkbun_dns_record resources with import blocks to stdout, the keys
+// are read from PORKBUN_API_KEY and PORKBUN_SECRET_KEY like the provider does
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s export -domain example.com > example.com.tf\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	domain := flags.String("domain", "", "the domain to export the records of")
+	if err := flags.Parse(args); err != nil {
+		// The flag set already printed the usage
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if *domain == "" {
+		flags.Usage()
+		return fmt.Errorf("-domain is required")
+	}
+
+	return provider.Export(ctx, os.Stdout, *domain)
+}